@@ -0,0 +1,85 @@
+package config
+
+import (
+	"context"
+	"log"
+	"time"
+
+	models "live_stream/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates the indexes the application relies on for uniqueness and lookups
+func EnsureIndexes(db *mongo.Database) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	indexes := map[string][]mongo.IndexModel{
+		"audiobooks": {
+			// Bulk imports upsert by the publisher's ID
+			{Keys: bson.D{{Key: "externalId", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		},
+		"audiobook_revisions": {
+			{Keys: bson.D{{Key: "audiobookId", Value: 1}, {Key: "revision", Value: -1}}, Options: options.Index().SetUnique(true)},
+		},
+		"slugs": {
+			{Keys: bson.D{{Key: "entityType", Value: 1}, {Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "entityType", Value: 1}, {Key: "entityId", Value: 1}}},
+		},
+		"audiobook_reactions": {
+			{Keys: bson.D{{Key: "audiobookId", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}}},
+		},
+		"comments": {
+			{Keys: bson.D{{Key: "audiobookId", Value: 1}, {Key: "parentId", Value: 1}, {Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "audiobookId", Value: 1}, {Key: "parentId", Value: 1}, {Key: "replyCount", Value: -1}, {Key: "timestamp", Value: -1}}},
+			{Keys: bson.D{{Key: "audiobookId", Value: 1}, {Key: "positionSeconds", Value: 1}}, Options: options.Index().SetSparse(true)},
+		},
+		"reviews": {
+			{Keys: bson.D{{Key: "audiobookId", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "audiobookId", Value: 1}, {Key: "status", Value: 1}, {Key: "helpfulCount", Value: -1}}},
+		},
+		"playback_progress": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "audiobookId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "updatedAt", Value: -1}}},
+		},
+		"bookmarks": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "audiobookId", Value: 1}, {Key: "positionSeconds", Value: 1}}},
+		},
+		"shelf_items": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "shelf", Value: 1}, {Key: "audiobookId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "shelf", Value: 1}, {Key: "addedAt", Value: -1}}},
+		},
+		"playlists": {
+			{Keys: bson.D{{Key: "ownerId", Value: 1}}},
+			{Keys: bson.D{{Key: "collaborators", Value: 1}}},
+			{Keys: bson.D{{Key: "shareToken", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		},
+		"listening_sessions": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "startedAt", Value: -1}}},
+			{
+				Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "eventId", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"eventId": bson.M{"$exists": true}}),
+			},
+		},
+		"ingested_events": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "eventId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			// Replays older than this are no longer recognized
+			{Keys: bson.D{{Key: "createdAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(models.IngestedEventRetention.Seconds()))},
+		},
+		"review_votes": {
+			{Keys: bson.D{{Key: "reviewId", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+	}
+
+	for collection, models := range indexes {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			log.Fatalf("Failed to create indexes on %s: %v", collection, err)
+		}
+	}
+
+	log.Println("✅ Indexes ensured")
+}
@@ -3,60 +3,115 @@ package controllers
 import (
 	"context"
 	"net/http"
+	"regexp"
+	"strings"
 	"time"
 
 	models "live_stream/models"
 	request "live_stream/models/requests"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AudiobookController struct {
 	AudiobookCol   *mongo.Collection
 	InteractionCol *mongo.Collection
+	ReactionCol    *mongo.Collection
+	RevisionCol    *mongo.Collection
+	CommentCol     *mongo.Collection
+	ReviewCol      *mongo.Collection
+	ReviewVoteCol  *mongo.Collection
+	ImportJobCol   *mongo.Collection
+	SlugCol        *mongo.Collection
+	UserCol        *mongo.Collection
+	RelatedCol     *mongo.Collection
+	CollectionCol  *mongo.Collection
+	ProgressCol    *mongo.Collection
+	BookmarkCol    *mongo.Collection
+	ShelfItemCol   *mongo.Collection
+	SessionCol     *mongo.Collection
+	PlaylistCol    *mongo.Collection
+	QueueCol       *mongo.Collection
+	Redis          *redis.Client
+	TrashRetention time.Duration // How long deleted audiobooks stay restorable before being purged
 }
 
-// GetAudiobooks - public endpoint to list all visible audiobooks
+// GetAudiobooks - public endpoint to list all currently published audiobooks.
+// Text is localized from Accept-Language (or ?lang=); ?language= filters by spoken language and
+// ?q= searches names and descriptions. Titles hidden by the viewer's content settings are left out.
 func (ac *AudiobookController) GetAudiobooks(c *gin.Context) {
-	cursor, err := ac.AudiobookCol.Find(context.TODO(), bson.M{"displayOnSite": true})
+	filter := publishedFilter(time.Now())
+	if lang := c.Query("language"); lang != "" {
+		locale, err := canonicalLocale(lang)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter["language"] = locale
+	}
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(q), Options: "i"}
+		filter["$and"] = bson.A{bson.M{"$or": bson.A{
+			bson.M{"name": pattern},
+			bson.M{"description": pattern},
+		}}}
+	}
+	loadContentPreferences(c, ac.UserCol).apply(filter)
+
+	cursor, err := ac.AudiobookCol.Find(context.TODO(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audiobooks"})
 		return
 	}
 	var audiobooks []models.Audiobook
 	cursor.All(context.TODO(), &audiobooks)
+
+	prefs := preferredLanguages(c)
+	for i := range audiobooks {
+		localizeAudiobook(&audiobooks[i], prefs)
+	}
+	c.Header("Vary", "Accept-Language")
 	c.JSON(http.StatusOK, audiobooks)
 }
 
-// GetAudiobookByID - public endpoint to get audiobook details + increment view count
+// GetAudiobookByID - public endpoint to get audiobook details (by ID or slug)
 func (ac *AudiobookController) GetAudiobookByID(c *gin.Context) {
-	id := c.Param("id")
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+	objID, ok := resolveAudiobookParam(c, ac.SlugCol)
+	if !ok {
 		return
 	}
 
+	filter := publishedFilter(time.Now())
+	filter["_id"] = objID
+
 	var audiobook models.Audiobook
-	err = ac.AudiobookCol.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&audiobook)
+	err := ac.AudiobookCol.FindOne(context.TODO(), filter).Decode(&audiobook)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
 		return
 	}
 
-	// Increment view count
-	ac.AudiobookCol.UpdateOne(
-		context.TODO(),
-		bson.M{"_id": objID},
-		bson.M{"$inc": bson.M{"viewCount": 1}},
-	)
+	if !loadContentPreferences(c, ac.UserCol).allows(audiobook) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":           "Audiobook is hidden by your content settings",
+			"ageRating":       audiobook.AgeRating,
+			"contentWarnings": audiobook.ContentWarnings,
+		})
+		return
+	}
 
-	// Update the viewCount in the response
-	audiobook.ViewCount++
+	// Views are counted by RecordPlay once the listener actually plays the audiobook
 
+	localizeAudiobook(&audiobook, preferredLanguages(c))
+	c.Header("Vary", "Accept-Language")
+	if audiobook.Locale != "" {
+		c.Header("Content-Language", audiobook.Locale)
+	}
 	c.JSON(http.StatusOK, audiobook)
 }
 
@@ -67,19 +122,50 @@ func (ac *AudiobookController) CreateAudiobook(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.DisplayOnSite != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "displayOnSite can't be set directly, use the publishing workflow"})
+		return
+	}
+
+	translations, err := translationsFromRequest(req.Translations)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var audioLanguage string
+	if req.Language != "" {
+		if audioLanguage, err = canonicalLocale(req.Language); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if req.AgeRating != "" && !isValidAgeRating(req.AgeRating) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid age rating"})
+		return
+	}
 
 	audiobook := models.Audiobook{
-		Name:          req.Name,
-		Description:   req.Description,
-		AudioData:     req.AudioData,
-		Thumbnail:     req.Thumbnail,
-		Content:       req.Content,
-		DisplayOnSite: req.DisplayOnSite,
-		ViewCount:     0,
-		Likes:         0,
-		Dislikes:      0,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		Name:            req.Name,
+		Description:     req.Description,
+		AudioData:       req.AudioData,
+		Thumbnail:       req.Thumbnail,
+		Content:         req.Content,
+		Language:        audioLanguage,
+		Translations:    translations,
+		Authors:         trimNames(req.Authors),
+		Genres:          normalizeTags(req.Genres),
+		AgeRating:       req.AgeRating,
+		ContentWarnings: normalizeTags(req.ContentWarnings),
+		DurationSeconds: req.DurationSeconds,
+		DisplayOnSite:   false,
+		Status:          models.AudiobookStatusDraft,
+		ViewCount:       0,
+		Likes:           0,
+		Dislikes:        0,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
 
 	result, err := ac.AudiobookCol.InsertOne(context.TODO(), audiobook)
@@ -88,6 +174,15 @@ func (ac *AudiobookController) CreateAudiobook(c *gin.Context) {
 		return
 	}
 
+	// Record the initial revision and slug
+	audiobookID := result.InsertedID.(primitive.ObjectID)
+	adminID, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	ac.updateAudiobookSlug(audiobookID, audiobook.Name)
+	if err := ac.recordRevision(audiobookID, metadataOf(audiobook), &adminID, 0); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Audiobook created but failed to record revision", "id": result.InsertedID})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Audiobook created", "id": result.InsertedID})
 }
 
@@ -105,6 +200,10 @@ func (ac *AudiobookController) UpdateAudiobook(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.DisplayOnSite != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "displayOnSite can't be set directly, use the publishing workflow"})
+		return
+	}
 
 	update := bson.M{}
 	if req.Name != "" {
@@ -122,30 +221,82 @@ func (ac *AudiobookController) UpdateAudiobook(c *gin.Context) {
 	if req.Content != "" {
 		update["content"] = req.Content
 	}
-	if req.DisplayOnSite != nil {
-		update["displayOnSite"] = *req.DisplayOnSite
+	if req.Language != "" {
+		locale, err := canonicalLocale(req.Language)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		update["language"] = locale
+	}
+	if req.Translations != nil {
+		translations, err := translationsFromRequest(req.Translations)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		update["translations"] = translations
+	}
+	if req.Authors != nil {
+		update["authors"] = trimNames(req.Authors)
+	}
+	if req.Genres != nil {
+		update["genres"] = normalizeTags(req.Genres)
+	}
+	if req.AgeRating != "" {
+		if !isValidAgeRating(req.AgeRating) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid age rating"})
+			return
+		}
+		update["ageRating"] = req.AgeRating
+	}
+	if req.ContentWarnings != nil {
+		update["contentWarnings"] = normalizeTags(req.ContentWarnings)
+	}
+	if req.DurationSeconds > 0 {
+		update["durationSeconds"] = req.DurationSeconds
 	}
 	update["updatedAt"] = time.Now()
 
-	result, err := ac.AudiobookCol.UpdateOne(
+	if err := ac.ensureBaselineRevision(objID); err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record revision"})
+		return
+	}
+
+	var updated models.Audiobook
+	err = ac.AudiobookCol.FindOneAndUpdate(
 		context.TODO(),
-		bson.M{"_id": objID},
+		bson.M{"_id": objID, "deletedAt": nil}, // Restore trashed audiobooks before editing them
 		bson.M{"$set": update},
-	)
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update audiobook"})
 		return
 	}
 
-	if result.MatchedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+	// Record who changed what
+	adminID, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if req.Name != "" {
+		ac.updateAudiobookSlug(objID, updated.Name)
+	}
+	if err := ac.recordRevision(objID, metadataOf(updated), &adminID, 0); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Audiobook updated but failed to record revision"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Audiobook updated"})
 }
 
-// DeleteAudiobook - admin endpoint to delete audiobook
+// DeleteAudiobook - admin endpoint to move an audiobook to the trash.
+// It can be restored until the trash retention period expires and it is purged.
 func (ac *AudiobookController) DeleteAudiobook(c *gin.Context) {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
@@ -154,187 +305,63 @@ func (ac *AudiobookController) DeleteAudiobook(c *gin.Context) {
 		return
 	}
 
-	result, err := ac.AudiobookCol.DeleteOne(context.TODO(), bson.M{"_id": objID})
+	adminID, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	result, err := ac.AudiobookCol.UpdateOne(
+		context.TODO(),
+		bson.M{"_id": objID, "deletedAt": nil},
+		bson.M{"$set": bson.M{
+			"deletedAt": time.Now(),
+			"deletedBy": adminID,
+		}},
+	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete audiobook"})
 		return
 	}
 
-	if result.DeletedCount == 0 {
+	if result.MatchedCount == 0 {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Audiobook deleted"})
+	c.JSON(http.StatusOK, gin.H{"message": "Audiobook moved to trash"})
 }
 
-// LikeAudiobook - user endpoint to like audiobook
+// LikeAudiobook - user endpoint to like audiobook (liking again removes the like)
 func (ac *AudiobookController) LikeAudiobook(c *gin.Context) {
-	id := c.Param("id")
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
-		return
-	}
-
-	userID := c.GetString("user_id")
-	uid, _ := primitive.ObjectIDFromHex(userID)
-
-	// Check if user has already liked
-	var existing models.AudiobookInteraction
-	err = ac.InteractionCol.FindOne(context.TODO(), bson.M{
-		"audiobookId": objID,
-		"userId":      uid,
-		"action":      "like",
-	}).Decode(&existing)
-
-	if err == mongo.ErrNoDocuments {
-		// User hasn't liked yet, add like
-		interaction := models.AudiobookInteraction{
-			AudiobookID: objID,
-			UserID:      uid,
-			Action:      "like",
-			CreatedAt:   time.Now(),
-		}
-		ac.InteractionCol.InsertOne(context.TODO(), interaction)
-
-		// Check and remove dislike if exists
-		ac.InteractionCol.DeleteOne(context.TODO(), bson.M{
-			"audiobookId": objID,
-			"userId":      uid,
-			"action":      "dislike",
-		})
-
-		// Update audiobook counts
-		ac.AudiobookCol.UpdateOne(
-			context.TODO(),
-			bson.M{"_id": objID},
-			bson.M{"$inc": bson.M{"likes": 1}},
-		)
-
-		// Decrement dislike if it existed
-		var audiobook models.Audiobook
-		ac.AudiobookCol.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&audiobook)
-		if audiobook.Dislikes > 0 {
-			ac.AudiobookCol.UpdateOne(
-				context.TODO(),
-				bson.M{"_id": objID},
-				bson.M{"$inc": bson.M{"dislikes": -1}},
-			)
-		}
-
-		c.JSON(http.StatusOK, gin.H{"message": "Audiobook liked"})
-	} else {
-		// User already liked, remove like
-		ac.InteractionCol.DeleteOne(context.TODO(), bson.M{
-			"audiobookId": objID,
-			"userId":      uid,
-			"action":      "like",
-		})
-
-		ac.AudiobookCol.UpdateOne(
-			context.TODO(),
-			bson.M{"_id": objID},
-			bson.M{"$inc": bson.M{"likes": -1}},
-		)
-
-		c.JSON(http.StatusOK, gin.H{"message": "Like removed"})
-	}
+	ac.react(c, models.ReactionLike)
 }
 
-// DislikeAudiobook - user endpoint to dislike audiobook
+// DislikeAudiobook - user endpoint to dislike audiobook (disliking again removes the dislike)
 func (ac *AudiobookController) DislikeAudiobook(c *gin.Context) {
-	id := c.Param("id")
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
-		return
-	}
-
-	userID := c.GetString("user_id")
-	uid, _ := primitive.ObjectIDFromHex(userID)
-
-	// Check if user has already disliked
-	var existing models.AudiobookInteraction
-	err = ac.InteractionCol.FindOne(context.TODO(), bson.M{
-		"audiobookId": objID,
-		"userId":      uid,
-		"action":      "dislike",
-	}).Decode(&existing)
-
-	if err == mongo.ErrNoDocuments {
-		// User hasn't disliked yet, add dislike
-		interaction := models.AudiobookInteraction{
-			AudiobookID: objID,
-			UserID:      uid,
-			Action:      "dislike",
-			CreatedAt:   time.Now(),
-		}
-		ac.InteractionCol.InsertOne(context.TODO(), interaction)
-
-		// Check and remove like if exists
-		ac.InteractionCol.DeleteOne(context.TODO(), bson.M{
-			"audiobookId": objID,
-			"userId":      uid,
-			"action":      "like",
-		})
-
-		// Update audiobook counts
-		ac.AudiobookCol.UpdateOne(
-			context.TODO(),
-			bson.M{"_id": objID},
-			bson.M{"$inc": bson.M{"dislikes": 1}},
-		)
-
-		// Decrement like if it existed
-		var audiobook models.Audiobook
-		ac.AudiobookCol.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&audiobook)
-		if audiobook.Likes > 0 {
-			ac.AudiobookCol.UpdateOne(
-				context.TODO(),
-				bson.M{"_id": objID},
-				bson.M{"$inc": bson.M{"likes": -1}},
-			)
-		}
-
-		c.JSON(http.StatusOK, gin.H{"message": "Audiobook disliked"})
-	} else {
-		// User already disliked, remove dislike
-		ac.InteractionCol.DeleteOne(context.TODO(), bson.M{
-			"audiobookId": objID,
-			"userId":      uid,
-			"action":      "dislike",
-		})
-
-		ac.AudiobookCol.UpdateOne(
-			context.TODO(),
-			bson.M{"_id": objID},
-			bson.M{"$inc": bson.M{"dislikes": -1}},
-		)
-
-		c.JSON(http.StatusOK, gin.H{"message": "Dislike removed"})
-	}
+	ac.react(c, models.ReactionDislike)
 }
 
-// GetAudiobookStats - public endpoint to get like/dislike/view counts
+// GetAudiobookStats - public endpoint to get like/dislike/view counts and the star rating summary
+// of a published audiobook
 func (ac *AudiobookController) GetAudiobookStats(c *gin.Context) {
-	id := c.Param("id")
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+	objID, ok := resolveAudiobookParam(c, ac.SlugCol)
+	if !ok {
 		return
 	}
 
+	filter := publishedFilter(time.Now())
+	filter["_id"] = objID
+
 	var audiobook models.Audiobook
-	err = ac.AudiobookCol.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&audiobook)
+	err := ac.AudiobookCol.FindOne(context.TODO(), filter).Decode(&audiobook)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"viewCount": audiobook.ViewCount,
-		"likes":     audiobook.Likes,
-		"dislikes":  audiobook.Dislikes,
+		"viewCount":          audiobook.ViewCount,
+		"likes":              audiobook.Likes,
+		"dislikes":           audiobook.Dislikes,
+		"ratingAverage":      audiobook.RatingAverage,
+		"ratingCount":        audiobook.RatingCount,
+		"ratingDistribution": audiobook.RatingDistribution,
 	})
 }
@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	models "live_stream/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// exportRecord is one audiobook as written to NDJSON exports
+type exportRecord struct {
+	ID           primitive.ObjectID `json:"id"`
+	ExternalID   string             `json:"externalId,omitempty"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	Content      string             `json:"content"`
+	Status       string             `json:"status"`
+	AudioData    string             `json:"audioData,omitempty"`
+	Thumbnail    string             `json:"thumbnail,omitempty"`
+	Stats        *exportStats       `json:"stats,omitempty"`
+	Comments     []models.Comment   `json:"comments,omitempty"`
+	CreatedAt    time.Time          `json:"createdAt"`
+	UpdatedAt    time.Time          `json:"updatedAt"`
+	PublishedAt  *time.Time         `json:"publishedAt,omitempty"`
+	CommentCount int                `json:"commentCount,omitempty"`
+}
+
+type exportStats struct {
+	ViewCount int `json:"viewCount"`
+	Likes     int `json:"likes"`
+	Dislikes  int `json:"dislikes"`
+}
+
+// onixProduct is a minimal ONIX-style product record for partner feeds
+type onixProduct struct {
+	XMLName          xml.Name       `xml:"Product"`
+	RecordReference  string         `xml:"RecordReference"`
+	ProductID        string         `xml:"ProductIdentifier>IDValue,omitempty"`
+	TitleText        string         `xml:"DescriptiveDetail>TitleDetail>TitleElement>TitleText"`
+	Description      string         `xml:"CollateralDetail>TextContent>Text"`
+	Content          string         `xml:"Content,omitempty"` // Transcription, not part of ONIX
+	Resources        []onixResource `xml:"CollateralDetail>SupportingResource,omitempty"`
+	PublishingStatus string         `xml:"PublishingDetail>PublishingStatus"`
+	PublishingDate   string         `xml:"PublishingDetail>PublishingDate>Date,omitempty"`
+	ViewCount        *int           `xml:"Stats>ViewCount,omitempty"`
+	Likes            *int           `xml:"Stats>Likes,omitempty"`
+	Dislikes         *int           `xml:"Stats>Dislikes,omitempty"`
+	CommentCount     *int           `xml:"Stats>CommentCount,omitempty"`
+}
+
+type onixResource struct {
+	ContentType string `xml:"ResourceContentType"` // ONIX code list 158: 01 = front cover, 15 = audio
+	Link        string `xml:"ResourceVersion>ResourceLink"`
+}
+
+var exportCSVHeader = []string{
+	"id", "externalId", "name", "description", "status", "createdAt", "updatedAt", "publishedAt",
+	"audioData", "thumbnail", "viewCount", "likes", "dislikes", "commentCount", "content",
+}
+
+// exportEnd closes every export, telling the client where to resume and whether the page
+// was cut short. Error is set when the export stopped early; resume from NextCursor.
+type exportEnd struct {
+	XMLName    xml.Name `json:"-" xml:"ExportEnd"`
+	End        bool     `json:"end" xml:"-"`
+	NextCursor string   `json:"nextCursor" xml:"nextCursor,attr"`
+	Error      string   `json:"error,omitempty" xml:"error,attr,omitempty"`
+}
+
+// ExportAudiobooks - admin endpoint to stream the catalog for backups and partner syndication.
+//
+// Query parameters:
+//   - format: ndjson (default), csv or xml (ONIX-style)
+//   - include: comma separated list of comments (ndjson only), stats, media
+//   - status: only export audiobooks in this lifecycle state
+//   - updatedSince: RFC3339 timestamp, only export audiobooks updated at or after it
+//   - after: resume after this audiobook ID (records are exported in ID order)
+//   - limit: maximum number of audiobooks in this page
+//
+// Records are streamed straight from the database cursor. The export always ends with a final
+// record (an {"end":true,...} line, a "#end" CSV row or an <ExportEnd> element) carrying the ID to
+// pass as `after` for the next page, empty once the export is complete, and an error if the export
+// stopped early. The cursor is also sent in the X-Next-Cursor trailer.
+func (ac *AudiobookController) ExportAudiobooks(c *gin.Context) {
+	format := c.DefaultQuery("format", "ndjson")
+	if format != "ndjson" && format != "csv" && format != "xml" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be ndjson, csv or xml"})
+		return
+	}
+
+	include := map[string]bool{}
+	for _, part := range strings.Split(c.Query("include"), ",") {
+		include[strings.TrimSpace(part)] = true
+	}
+	if include["comments"] && format != "ndjson" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comments can only be exported as ndjson"})
+		return
+	}
+
+	filter := bson.M{"deletedAt": nil}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if since := c.Query("updatedSince"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "updatedSince must be an RFC3339 timestamp"})
+			return
+		}
+		filter["updatedAt"] = bson.M{"$gte": t}
+	}
+	if after := c.Query("after"); after != "" {
+		afterID, err := primitive.ObjectIDFromHex(after)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid after cursor"})
+			return
+		}
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetBatchSize(100)
+	limit, _ := strconv.ParseInt(c.Query("limit"), 10, 64)
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	if !include["media"] {
+		opts.SetProjection(bson.M{"audioData": 0})
+	}
+
+	ctx := c.Request.Context()
+	cursor, err := ac.AudiobookCol.Find(ctx, filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export audiobooks"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	contentTypes := map[string]string{
+		"ndjson": "application/x-ndjson",
+		"csv":    "text/csv; charset=utf-8",
+		"xml":    "application/xml; charset=utf-8",
+	}
+	c.Header("Content-Type", contentTypes[format])
+	c.Header("Content-Disposition", "attachment; filename=audiobooks-export."+format)
+	c.Header("Trailer", "X-Next-Cursor")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	jsonEnc := json.NewEncoder(w)
+	csvWriter := csv.NewWriter(w)
+	xmlEnc := xml.NewEncoder(w)
+
+	switch format {
+	case "csv":
+		csvWriter.Write(exportCSVHeader)
+	case "xml":
+		w.WriteString(xml.Header)
+		w.WriteString("<ONIXMessage release=\"3.0\">\n<Header><SentDateTime>" + time.Now().UTC().Format("20060102T150405Z") + "</SentDateTime></Header>\n")
+	}
+
+	// A record that can't be read stops the export rather than going missing from it
+	var lastID primitive.ObjectID
+	var count int64
+	var exportErr error
+	for cursor.Next(ctx) {
+		var audiobook models.Audiobook
+		if err := cursor.Decode(&audiobook); err != nil {
+			exportErr = fmt.Errorf("failed to read audiobook %v: %v", cursor.Current.Lookup("_id"), err)
+			break
+		}
+
+		var comments []models.Comment
+		commentCount := 0
+		if include["comments"] {
+			var err error
+			if comments, err = ac.exportComments(ctx, audiobook.ID); err != nil {
+				exportErr = fmt.Errorf("failed to read comments of audiobook %s: %v", audiobook.ID.Hex(), err)
+				break
+			}
+			commentCount = len(comments)
+		} else if include["stats"] {
+			n, err := ac.CommentCol.CountDocuments(ctx, bson.M{"audiobookId": audiobook.ID, "isDeleted": false})
+			if err != nil {
+				exportErr = fmt.Errorf("failed to count comments of audiobook %s: %v", audiobook.ID.Hex(), err)
+				break
+			}
+			commentCount = int(n)
+		}
+		lastID = audiobook.ID
+		count++
+
+		switch format {
+		case "ndjson":
+			record := exportRecord{
+				ID:          audiobook.ID,
+				ExternalID:  audiobook.ExternalID,
+				Name:        audiobook.Name,
+				Description: audiobook.Description,
+				Content:     audiobook.Content,
+				Status:      audiobook.Status,
+				CreatedAt:   audiobook.CreatedAt,
+				UpdatedAt:   audiobook.UpdatedAt,
+				PublishedAt: audiobook.PublishedAt,
+			}
+			if include["media"] {
+				record.AudioData = audiobook.AudioData
+				record.Thumbnail = audiobook.Thumbnail
+			}
+			if include["stats"] {
+				record.Stats = &exportStats{ViewCount: audiobook.ViewCount, Likes: audiobook.Likes, Dislikes: audiobook.Dislikes}
+				record.CommentCount = commentCount
+			}
+			if include["comments"] {
+				record.Comments = comments
+			}
+			jsonEnc.Encode(record)
+		case "csv":
+			row := []string{
+				audiobook.ID.Hex(), audiobook.ExternalID, audiobook.Name, audiobook.Description, audiobook.Status,
+				audiobook.CreatedAt.Format(time.RFC3339), audiobook.UpdatedAt.Format(time.RFC3339), formatOptionalTime(audiobook.PublishedAt),
+				"", "", "", "", "", "", audiobook.Content,
+			}
+			if include["media"] {
+				row[8], row[9] = audiobook.AudioData, audiobook.Thumbnail
+			}
+			if include["stats"] {
+				row[10] = strconv.Itoa(audiobook.ViewCount)
+				row[11] = strconv.Itoa(audiobook.Likes)
+				row[12] = strconv.Itoa(audiobook.Dislikes)
+				row[13] = strconv.Itoa(commentCount)
+			}
+			csvWriter.Write(row)
+			csvWriter.Flush()
+		case "xml":
+			xmlEnc.Encode(onixProductOf(audiobook, include, commentCount))
+			w.WriteString("\n")
+		}
+
+		if count%100 == 0 {
+			w.Flush()
+		}
+	}
+
+	// A full page means there may be more to fetch; a failed one resumes after the last record sent
+	end := exportEnd{End: true}
+	if exportErr == nil {
+		exportErr = cursor.Err()
+	}
+	if exportErr != nil {
+		end.Error = "Export interrupted: " + exportErr.Error()
+		if !lastID.IsZero() {
+			end.NextCursor = lastID.Hex()
+		} else {
+			end.NextCursor = c.Query("after")
+		}
+	} else if limit > 0 && count == limit {
+		end.NextCursor = lastID.Hex()
+	}
+
+	switch format {
+	case "ndjson":
+		jsonEnc.Encode(end)
+	case "csv":
+		csvWriter.Write([]string{"#end", end.NextCursor, end.Error})
+		csvWriter.Flush()
+	case "xml":
+		xmlEnc.Encode(end)
+		w.WriteString("\n</ONIXMessage>\n")
+	}
+	w.Header().Set("X-Next-Cursor", end.NextCursor)
+	w.Flush()
+}
+
+func (ac *AudiobookController) exportComments(ctx context.Context, audiobookID primitive.ObjectID) ([]models.Comment, error) {
+	cursor, err := ac.CommentCol.Find(ctx, bson.M{"audiobookId": audiobookID, "isDeleted": false})
+	if err != nil {
+		return nil, err
+	}
+	comments := []models.Comment{}
+	err = cursor.All(ctx, &comments)
+	return comments, err
+}
+
+func onixProductOf(audiobook models.Audiobook, include map[string]bool, commentCount int) onixProduct {
+	product := onixProduct{
+		RecordReference: audiobook.ID.Hex(),
+		ProductID:       audiobook.ExternalID,
+		TitleText:       audiobook.Name,
+		Description:     audiobook.Description,
+		Content:         audiobook.Content,
+		// ONIX code list 64: 04 = active, 01 = unspecified
+		PublishingStatus: "01",
+	}
+	if audiobook.Status == models.AudiobookStatusPublished {
+		product.PublishingStatus = "04"
+	}
+	if audiobook.PublishedAt != nil {
+		product.PublishingDate = audiobook.PublishedAt.Format("20060102")
+	}
+	if include["media"] {
+		if audiobook.Thumbnail != "" {
+			product.Resources = append(product.Resources, onixResource{ContentType: "01", Link: audiobook.Thumbnail})
+		}
+		if audiobook.AudioData != "" {
+			product.Resources = append(product.Resources, onixResource{ContentType: "15", Link: audiobook.AudioData})
+		}
+	}
+	if include["stats"] {
+		product.ViewCount = &audiobook.ViewCount
+		product.Likes = &audiobook.Likes
+		product.Dislikes = &audiobook.Dislikes
+		product.CommentCount = &commentCount
+	}
+	return product
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,554 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Planned outcome of a single import row
+const (
+	importActionCreate    = "create"
+	importActionUpdate    = "update"
+	importActionUnchanged = "unchanged"
+	importActionError     = "error"
+)
+
+// A pending or running job that hasn't saved progress for this long was interrupted
+const importJobStaleAfter = 15 * time.Minute
+
+// Running jobs save their progress at least this often, even on slow rows
+const importJobHeartbeat = time.Minute
+
+type importRowPlan struct {
+	Row        int    `json:"row"`
+	ExternalID string `json:"externalId"`
+	Action     string `json:"action"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ImportAudiobooks - admin endpoint to bulk import audiobooks from a CSV or JSON manifest.
+//
+// Multipart form fields:
+//   - manifest: the CSV or JSON file (format taken from ?format= or the file extension)
+//   - media: any number of media files referenced by name from audioData/thumbnail
+//
+// With ?dryRun=true every row is validated and the planned action is returned without writing.
+// Otherwise the import runs as a background job whose progress is available at GET /admin/imports/:id.
+// Rows are matched on externalId, so re-uploading the same manifest is idempotent.
+func (ac *AudiobookController) ImportAudiobooks(c *gin.Context) {
+	adminID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	manifest, err := c.FormFile("manifest")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "manifest file is required"})
+		return
+	}
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(manifest.Filename)), ".")
+	}
+
+	file, err := manifest.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read manifest"})
+		return
+	}
+	defer file.Close()
+
+	rows, err := parseImportManifest(file, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, spoolDir, err := spoolImportMedia(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("dryRun") == "true" {
+		defer os.RemoveAll(spoolDir)
+		plans := ac.planImport(rows, media, os.Getenv("MEDIA_DIR"))
+		summary := map[string]int{}
+		for _, p := range plans {
+			summary[p.Action]++
+		}
+		c.JSON(http.StatusOK, gin.H{"totalRows": len(rows), "summary": summary, "rows": plans})
+		return
+	}
+
+	now := time.Now()
+	job := models.ImportJob{
+		Format:    format,
+		Status:    models.ImportJobPending,
+		TotalRows: len(rows),
+		Errors:    []models.ImportRowError{},
+		CreatedBy: adminID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	result, err := ac.ImportJobCol.InsertOne(context.TODO(), job)
+	if err != nil {
+		os.RemoveAll(spoolDir)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create import job"})
+		return
+	}
+	job.ID = result.InsertedID.(primitive.ObjectID)
+
+	go func() {
+		defer os.RemoveAll(spoolDir)
+		ac.runImport(job, rows, media)
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Import started", "id": job.ID})
+}
+
+// GetImportJobs - admin endpoint to list import jobs, newest first
+func (ac *AudiobookController) GetImportJobs(c *gin.Context) {
+	cursor, err := ac.ImportJobCol.Find(context.TODO(), bson.M{}, options.Find().SetSort(bson.M{"createdAt": -1}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch import jobs"})
+		return
+	}
+	jobs := []models.ImportJob{}
+	cursor.All(context.TODO(), &jobs)
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetImportJob - admin endpoint to check the progress of an import job
+func (ac *AudiobookController) GetImportJob(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import job ID"})
+		return
+	}
+
+	var job models.ImportJob
+	if err := ac.ImportJobCol.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&job); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import job not found"})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+func parseImportManifest(r io.Reader, format string) ([]request.ImportAudiobookRow, error) {
+	switch format {
+	case "json":
+		var rows []request.ImportAudiobookRow
+		if err := json.NewDecoder(r).Decode(&rows); err != nil {
+			return nil, fmt.Errorf("invalid JSON manifest: %v", err)
+		}
+		return rows, nil
+	case "csv":
+		records, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV manifest: %v", err)
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("CSV manifest has no header row")
+		}
+
+		columns := map[string]int{}
+		for i, name := range records[0] {
+			columns[strings.TrimSpace(name)] = i
+		}
+		get := func(record []string, name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		rows := make([]request.ImportAudiobookRow, 0, len(records)-1)
+		for _, record := range records[1:] {
+			// The closing row of a CSV export
+			if len(record) > 0 && record[0] == "#end" {
+				continue
+			}
+			rows = append(rows, request.ImportAudiobookRow{
+				ExternalID:  get(record, "externalId"),
+				Name:        get(record, "name"),
+				Description: get(record, "description"),
+				AudioData:   get(record, "audioData"),
+				Thumbnail:   get(record, "thumbnail"),
+				Content:     get(record, "content"),
+			})
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("unsupported manifest format %q, expected csv or json", format)
+	}
+}
+
+// spoolImportMedia copies the uploaded media files to a temporary directory, since the multipart
+// temp files are gone once the request returns. It returns the spooled paths keyed by file name;
+// the caller removes the directory when done.
+func spoolImportMedia(c *gin.Context) (map[string]string, string, error) {
+	media := map[string]string{}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["media"]) == 0 {
+		return media, "", nil
+	}
+
+	dir, err := os.MkdirTemp("", "audiobook-import-")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to store media files")
+	}
+	for _, fh := range form.File["media"] {
+		name := filepath.Base(fh.Filename)
+		path := filepath.Join(dir, name)
+		if err := copyUploadedFile(fh, path); err != nil {
+			os.RemoveAll(dir)
+			return nil, "", fmt.Errorf("failed to read media file %s", fh.Filename)
+		}
+		media[name] = path
+	}
+	return media, dir, nil
+}
+
+func copyUploadedFile(fh *multipart.FileHeader, path string) error {
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	return copyToFile(src, path)
+}
+
+func copyToFile(src io.Reader, path string) error {
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// isMediaFileRef tells whether an audioData/thumbnail value names a media file, as opposed to
+// a URL or inline base64 data
+func isMediaFileRef(ref string) bool {
+	return ref != "" && !strings.Contains(ref, "://") && !strings.HasPrefix(ref, "data:") &&
+		len(ref) <= 255 && filepath.Ext(ref) != ""
+}
+
+// missingImportMedia returns the first media file a row references that was neither uploaded
+// nor already stored under mediaDir
+func missingImportMedia(row request.ImportAudiobookRow, media map[string]string, mediaDir string) string {
+	for _, ref := range []string{row.AudioData, row.Thumbnail} {
+		if !isMediaFileRef(ref) {
+			continue
+		}
+		if _, ok := media[ref]; ok {
+			continue
+		}
+		if mediaDir != "" {
+			if _, err := os.Stat(filepath.Join(mediaDir, filepath.Clean("/"+ref))); err == nil {
+				continue
+			}
+		}
+		return ref
+	}
+	return ""
+}
+
+func validateImportRow(row request.ImportAudiobookRow) error {
+	switch {
+	case row.ExternalID == "":
+		return fmt.Errorf("externalId is required")
+	case row.Name == "":
+		return fmt.Errorf("name is required")
+	case row.Description == "":
+		return fmt.Errorf("description is required")
+	case row.AudioData == "":
+		return fmt.Errorf("audioData is required")
+	}
+	return nil
+}
+
+// planImport validates every row and works out what importing it would do, without writing anything
+func (ac *AudiobookController) planImport(rows []request.ImportAudiobookRow, media map[string]string, mediaDir string) []importRowPlan {
+	plans := make([]importRowPlan, 0, len(rows))
+	seen := map[string]bool{}
+
+	for i, row := range rows {
+		plan := importRowPlan{Row: i + 1, ExternalID: row.ExternalID}
+
+		if err := validateImportRow(row); err != nil {
+			plan.Action, plan.Error = importActionError, err.Error()
+		} else if seen[row.ExternalID] {
+			plan.Action, plan.Error = importActionError, "duplicate externalId in manifest"
+		} else if missing := missingImportMedia(row, media, mediaDir); missing != "" {
+			plan.Action, plan.Error = importActionError, "media file "+missing+" was not uploaded"
+		} else {
+			seen[row.ExternalID] = true
+
+			var existing models.Audiobook
+			err := ac.AudiobookCol.FindOne(context.TODO(), bson.M{"externalId": row.ExternalID}).Decode(&existing)
+			if err == mongo.ErrNoDocuments {
+				plan.Action = importActionCreate
+			} else if err != nil {
+				plan.Action, plan.Error = importActionError, "failed to look up existing audiobook"
+			} else if imported, err := importedFields(row, media, mediaDir); err != nil {
+				plan.Action, plan.Error = importActionError, err.Error()
+			} else if len(diffMetadata(metadataOf(existing), metadataOf(withImportedFields(existing, imported)))) == 0 {
+				plan.Action = importActionUnchanged
+			} else {
+				plan.Action = importActionUpdate
+			}
+		}
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+// importedFields builds the fields a row sets on an audiobook, resolving references to uploaded media.
+// With an empty mediaDir, uploaded media is inlined as base64, read only for the row at hand.
+func importedFields(row request.ImportAudiobookRow, media map[string]string, mediaDir string) (models.Audiobook, error) {
+	resolve := func(ref string) (string, error) {
+		path, ok := media[ref]
+		if !ok {
+			return ref, nil
+		}
+		if mediaDir == "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return "", fmt.Errorf("failed to read media file %s", ref)
+			}
+			return base64.StdEncoding.EncodeToString(data), nil
+		}
+		return filepath.Join("imports", filepath.Base(row.ExternalID), ref), nil
+	}
+
+	audioData, err := resolve(row.AudioData)
+	if err != nil {
+		return models.Audiobook{}, err
+	}
+	thumbnail, err := resolve(row.Thumbnail)
+	if err != nil {
+		return models.Audiobook{}, err
+	}
+	return models.Audiobook{
+		Name:        row.Name,
+		Description: row.Description,
+		AudioData:   audioData,
+		Thumbnail:   thumbnail,
+		Content:     row.Content,
+	}, nil
+}
+
+// withImportedFields is the audiobook as it will be once the imported fields are applied
+func withImportedFields(a, imported models.Audiobook) models.Audiobook {
+	a.Name = imported.Name
+	a.Description = imported.Description
+	a.AudioData = imported.AudioData
+	a.Thumbnail = imported.Thumbnail
+	a.Content = imported.Content
+	return a
+}
+
+// saveImportMedia copies the uploaded media referenced by a row under MEDIA_DIR
+func saveImportMedia(row request.ImportAudiobookRow, media map[string]string, mediaDir string) error {
+	for _, ref := range []string{row.AudioData, row.Thumbnail} {
+		spooled, ok := media[ref]
+		if !ok {
+			continue
+		}
+		path := filepath.Join(mediaDir, "imports", filepath.Base(row.ExternalID), ref)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		src, err := os.Open(spooled)
+		if err != nil {
+			return err
+		}
+		err = copyToFile(src, path)
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runImport applies the manifest rows and keeps the job document up to date
+func (ac *AudiobookController) runImport(job models.ImportJob, rows []request.ImportAudiobookRow, media map[string]string) {
+	ctx := context.Background()
+	mediaDir := os.Getenv("MEDIA_DIR")
+
+	job.Status = models.ImportJobRunning
+	ac.saveImportJob(&job)
+
+	plans := ac.planImport(rows, media, mediaDir)
+	for i, row := range rows {
+		plan := plans[i]
+
+		var err error
+		switch plan.Action {
+		case importActionError:
+			err = fmt.Errorf("%s", plan.Error)
+		case importActionCreate, importActionUpdate:
+			err = ac.applyImportRow(ctx, job, row, media, mediaDir, plan.Action)
+		}
+
+		switch {
+		case err != nil:
+			job.Failed++
+			job.Errors = append(job.Errors, models.ImportRowError{Row: plan.Row, ExternalID: row.ExternalID, Error: err.Error()})
+		case plan.Action == importActionCreate:
+			job.Created++
+		case plan.Action == importActionUpdate:
+			job.Updated++
+		default:
+			job.Unchanged++
+		}
+
+		job.Processed++
+		if job.Processed%50 == 0 || time.Since(job.UpdatedAt) > importJobHeartbeat {
+			ac.saveImportJob(&job)
+		}
+	}
+
+	job.Status = models.ImportJobCompleted
+	if job.Failed == job.TotalRows && job.TotalRows > 0 {
+		job.Status = models.ImportJobFailed
+	}
+	ac.saveImportJob(&job)
+}
+
+func (ac *AudiobookController) applyImportRow(ctx context.Context, job models.ImportJob, row request.ImportAudiobookRow, media map[string]string, mediaDir, action string) error {
+	if mediaDir != "" {
+		if err := saveImportMedia(row, media, mediaDir); err != nil {
+			return fmt.Errorf("failed to store media: %v", err)
+		}
+	}
+
+	imported, err := importedFields(row, media, mediaDir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	if action == importActionCreate {
+		audiobook := models.Audiobook{
+			ExternalID:  row.ExternalID,
+			Name:        imported.Name,
+			Description: imported.Description,
+			AudioData:   imported.AudioData,
+			Thumbnail:   imported.Thumbnail,
+			Content:     imported.Content,
+			Status:      models.AudiobookStatusDraft,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		result, err := ac.AudiobookCol.InsertOne(ctx, audiobook)
+		if err == nil {
+			audiobookID := result.InsertedID.(primitive.ObjectID)
+			ac.updateAudiobookSlug(audiobookID, imported.Name)
+			if err := ac.recordRevision(audiobookID, metadataOf(audiobook), &job.CreatedBy, 0); err != nil {
+				return fmt.Errorf("audiobook created but failed to record revision")
+			}
+			return nil
+		}
+		// A concurrent import created it first: update that one instead
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to create audiobook")
+		}
+	}
+
+	var existing models.Audiobook
+	if err := ac.AudiobookCol.FindOne(ctx, bson.M{"externalId": row.ExternalID}).Decode(&existing); err != nil {
+		return fmt.Errorf("failed to look up existing audiobook")
+	}
+	if err := ac.ensureBaselineRevision(existing.ID); err != nil {
+		return fmt.Errorf("failed to record revision")
+	}
+
+	var updated models.Audiobook
+	err = ac.AudiobookCol.FindOneAndUpdate(ctx,
+		bson.M{"_id": existing.ID},
+		bson.M{"$set": bson.M{
+			"name":        imported.Name,
+			"description": imported.Description,
+			"audioData":   imported.AudioData,
+			"thumbnail":   imported.Thumbnail,
+			"content":     imported.Content,
+			"updatedAt":   now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		return fmt.Errorf("failed to update audiobook")
+	}
+	ac.updateAudiobookSlug(existing.ID, imported.Name)
+	if err := ac.recordRevision(existing.ID, metadataOf(updated), &job.CreatedBy, 0); err != nil {
+		return fmt.Errorf("audiobook updated but failed to record revision")
+	}
+	return nil
+}
+
+func (ac *AudiobookController) saveImportJob(job *models.ImportJob) {
+	job.UpdatedAt = time.Now()
+	_, err := ac.ImportJobCol.ReplaceOne(context.Background(), bson.M{"_id": job.ID}, job)
+	if err != nil {
+		log.Printf("Import: failed to save job %s: %v", job.ID.Hex(), err)
+	}
+}
+
+// RunImportJobReaper marks import jobs that stopped saving progress as failed, e.g. because the
+// server restarted in the middle of them. Their rows can be imported again by re-uploading the
+// manifest. It blocks, so start it in its own goroutine.
+func (ac *AudiobookController) RunImportJobReaper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		result, err := ac.ImportJobCol.UpdateMany(context.Background(),
+			bson.M{
+				"status":    bson.M{"$in": bson.A{models.ImportJobPending, models.ImportJobRunning}},
+				"updatedAt": bson.M{"$lt": now.Add(-importJobStaleAfter)},
+			},
+			bson.M{"$set": bson.M{
+				"status":    models.ImportJobFailed,
+				"error":     "Import was interrupted, upload the manifest again to finish it",
+				"updatedAt": now,
+			}},
+		)
+		if err != nil {
+			log.Println("Import reaper: failed to update jobs:", err)
+		} else if result.ModifiedCount > 0 {
+			log.Printf("Import reaper: marked %d interrupted import(s) as failed", result.ModifiedCount)
+		}
+		<-ticker.C
+	}
+}
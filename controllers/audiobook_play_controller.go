@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// A play only counts as a view once this much has been listened to
+	minQualifiedPlaySeconds = 30
+	// The same listener is counted at most once per audiobook within this window
+	playDedupWindow = 30 * time.Minute
+
+	playDedupPrefix    = "play:"
+	pendingViewsKey    = "views:pending"  // Hash: audiobook ID -> views not yet written to Mongo
+	flushingViewsKey   = "views:flushing" // Snapshot of pending views being written
+	flushIDKey         = "views:flushing:id"
+	viewFlushLockKey   = "views:flush-lock" // Held by the instance currently flushing
+	viewFlushLockTTL   = 5 * time.Minute
+	viewFlushBatchSize = 500
+)
+
+// releaseLock deletes a lock only while it still holds this owner's token
+var releaseLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Crawlers, link previewers and HTTP libraries that should never count as listeners
+var botUserAgent = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|preview|facebookexternalhit|headless|curl|wget|python-requests|go-http-client|okhttp|java/|^$`)
+
+// RecordPlay - public endpoint the player calls after a listening session.
+// The audiobook's view count goes up only for qualified plays (30+ seconds) from real clients,
+// once per user (or per IP for anonymous listeners) within the dedup window.
+func (ac *AudiobookController) RecordPlay(c *gin.Context) {
+	objID, ok := resolveAudiobookParam(c, ac.SlugCol)
+	if !ok {
+		return
+	}
+
+	var req request.RecordPlayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.SecondsListened < minQualifiedPlaySeconds {
+		c.JSON(http.StatusOK, gin.H{"counted": false, "reason": "Play too short"})
+		return
+	}
+	if botUserAgent.MatchString(c.GetHeader("User-Agent")) {
+		c.JSON(http.StatusOK, gin.H{"counted": false, "reason": "Automated client"})
+		return
+	}
+
+	ctx := context.TODO()
+	count, err := ac.AudiobookCol.CountDocuments(ctx, bson.M{"_id": objID, "deletedAt": nil})
+	if err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return
+	}
+
+	listener := "ip:" + c.ClientIP()
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err == nil {
+		listener = "user:" + uid.Hex()
+	}
+
+	first, err := ac.Redis.SetNX(ctx, playDedupPrefix+objID.Hex()+":"+listener, 1, playDedupWindow).Result()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record play"})
+		return
+	}
+	if !first {
+		c.JSON(http.StatusOK, gin.H{"counted": false, "reason": "Already counted recently"})
+		return
+	}
+
+	if err := ac.Redis.HIncrBy(ctx, pendingViewsKey, objID.Hex(), 1).Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record play"})
+		return
+	}
+	recordEngagement(ac.Redis, objID, "view")
+
+	// Logged-in listens feed related titles and recommendations
+	if !uid.IsZero() {
+		ac.InteractionCol.UpdateOne(ctx,
+			bson.M{"audiobookId": objID, "userId": uid, "action": "view"},
+			bson.M{"$setOnInsert": models.AudiobookInteraction{AudiobookID: objID, UserID: uid, Action: "view", CreatedAt: time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"counted": true})
+}
+
+// RunViewCountFlusher periodically writes the view counts buffered in Redis to Mongo.
+// It blocks, so start it in its own goroutine.
+func (ac *AudiobookController) RunViewCountFlusher(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := ac.flushViewCounts(); err != nil {
+			log.Println("View count flush failed:", err)
+		}
+		<-ticker.C
+	}
+}
+
+// flushViewCounts moves the pending counters aside, so new plays keep accumulating while the
+// snapshot is applied with bulk writes. A snapshot left over from a failed run is retried first.
+//
+// Only one instance flushes at a time. Each snapshot has an ID that is stamped on the audiobooks
+// it was applied to, so retrying a partly applied snapshot never counts a view twice.
+func (ac *AudiobookController) flushViewCounts() error {
+	if ac.Redis == nil {
+		return nil
+	}
+	ctx := context.Background()
+
+	token := primitive.NewObjectID().Hex()
+	locked, err := ac.Redis.SetNX(ctx, viewFlushLockKey, token, viewFlushLockTTL).Result()
+	if err != nil || !locked {
+		return err
+	}
+	defer releaseLock.Run(ctx, ac.Redis, []string{viewFlushLockKey}, token)
+
+	leftover, err := ac.Redis.Exists(ctx, flushingViewsKey).Result()
+	if err != nil {
+		return err
+	}
+	if leftover == 0 {
+		pendingCount, err := ac.Redis.Exists(ctx, pendingViewsKey).Result()
+		if err != nil || pendingCount == 0 {
+			return err
+		}
+		// A new snapshot gets a new ID before any of it can be applied
+		if err := ac.Redis.Set(ctx, flushIDKey, primitive.NewObjectID().Hex(), 0).Err(); err != nil {
+			return err
+		}
+		if err := ac.Redis.Rename(ctx, pendingViewsKey, flushingViewsKey).Err(); err != nil {
+			return err
+		}
+	}
+
+	flushID, err := ac.Redis.Get(ctx, flushIDKey).Result()
+	if err == redis.Nil {
+		// The snapshot was taken but never stamped, so none of it was applied yet
+		flushID = primitive.NewObjectID().Hex()
+		err = ac.Redis.Set(ctx, flushIDKey, flushID, 0).Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	pending, err := ac.Redis.HGetAll(ctx, flushingViewsKey).Result()
+	if err != nil {
+		return err
+	}
+
+	writes := make([]mongo.WriteModel, 0, viewFlushBatchSize)
+	apply := func() error {
+		if len(writes) == 0 {
+			return nil
+		}
+		_, err := ac.AudiobookCol.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		writes = writes[:0]
+		return err
+	}
+	for hex, n := range pending {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			continue
+		}
+		views, err := strconv.Atoi(n)
+		if err != nil || views <= 0 {
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "viewFlushId": bson.M{"$ne": flushID}}).
+			SetUpdate(bson.M{
+				"$inc": bson.M{"viewCount": views},
+				"$set": bson.M{"viewFlushId": flushID},
+			}))
+		if len(writes) == viewFlushBatchSize {
+			if err := apply(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+
+	return ac.Redis.Del(ctx, flushingViewsKey, flushIDKey).Err()
+}
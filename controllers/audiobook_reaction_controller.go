@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	models "live_stream/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var errAudiobookNotFound = errors.New("audiobook not found")
+
+// Response messages per reaction: [added, removed]
+var reactionMessages = map[string][2]string{
+	models.ReactionLike:    {"Audiobook liked", "Like removed"},
+	models.ReactionDislike: {"Audiobook disliked", "Dislike removed"},
+}
+
+// react toggles the user's reaction to an audiobook. The reaction document and the audiobook's
+// counters change together in one transaction, and the unique (audiobookId, userId) index
+// stops double submits from creating a second reaction.
+func (ac *AudiobookController) react(c *gin.Context, reaction string) {
+	objID, ok := resolveAudiobookParam(c, ac.SlugCol)
+	if !ok {
+		return
+	}
+
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var message string
+	added := false
+	err = withTransaction(context.TODO(), ac.AudiobookCol.Database().Client(), func(ctx context.Context) error {
+		key := bson.M{"audiobookId": objID, "userId": uid}
+		now := time.Now()
+		inc := bson.M{}
+		added = false
+
+		var existing models.AudiobookReaction
+		err := ac.ReactionCol.FindOne(ctx, key).Decode(&existing)
+		switch {
+		case err == mongo.ErrNoDocuments:
+			_, err = ac.ReactionCol.InsertOne(ctx, models.AudiobookReaction{
+				AudiobookID: objID,
+				UserID:      uid,
+				Reaction:    reaction,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			})
+			inc[reactionCounter(reaction)] = 1
+			message = reactionMessages[reaction][0]
+			added = true
+		case err != nil:
+			return err
+		case existing.Reaction == reaction:
+			_, err = ac.ReactionCol.DeleteOne(ctx, bson.M{"_id": existing.ID})
+			inc[reactionCounter(reaction)] = -1
+			message = reactionMessages[reaction][1]
+		default:
+			_, err = ac.ReactionCol.UpdateOne(ctx,
+				bson.M{"_id": existing.ID},
+				bson.M{"$set": bson.M{"reaction": reaction, "updatedAt": now}},
+			)
+			inc[reactionCounter(reaction)] = 1
+			inc[reactionCounter(existing.Reaction)] = -1
+			message = reactionMessages[reaction][0]
+			added = true
+		}
+		if err != nil {
+			return err
+		}
+
+		result, err := ac.AudiobookCol.UpdateOne(ctx, bson.M{"_id": objID, "deletedAt": nil}, bson.M{"$inc": inc})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errAudiobookNotFound
+		}
+		return nil
+	})
+
+	switch {
+	case err == errAudiobookNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+	case mongo.IsDuplicateKeyError(err):
+		c.JSON(http.StatusConflict, gin.H{"error": "Reaction was changed concurrently, please retry"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reaction"})
+	default:
+		if added && reaction == models.ReactionLike {
+			recordEngagement(ac.Redis, objID, "like")
+		}
+		c.JSON(http.StatusOK, gin.H{"message": message})
+	}
+}
+
+// reactionCounter is the audiobook field counting a reaction ("likes" / "dislikes")
+func reactionCounter(reaction string) string {
+	return reaction + "s"
+}
+
+// withTransaction runs fn in a transaction, retried by the driver on transient errors.
+// Standalone MongoDB servers do not support transactions; there fn runs without one and
+// the reaction reconciler corrects any counter drift.
+func withTransaction(ctx context.Context, client *mongo.Client, fn func(ctx context.Context) error) error {
+	session, err := client.StartSession()
+	if err != nil {
+		return fn(ctx)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == 20 { // IllegalOperation: not a replica set
+		return fn(ctx)
+	}
+	return err
+}
+
+// RunReactionReconciler moves legacy like/dislike interactions into reactions once, then
+// periodically recomputes every audiobook's likes/dislikes from the reactions.
+// It blocks, so start it in its own goroutine.
+func (ac *AudiobookController) RunReactionReconciler(interval time.Duration) {
+	if err := ac.migrateLegacyReactions(); err != nil {
+		log.Println("Reaction migration failed:", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := ac.reconcileReactionCounts(); err != nil {
+			log.Println("Reaction reconciliation failed:", err)
+		}
+		<-ticker.C
+	}
+}
+
+// migrateLegacyReactions converts like/dislike interactions from before reactions existed.
+// When a user has both (or duplicates), the most recent one wins.
+func (ac *AudiobookController) migrateLegacyReactions() error {
+	ctx := context.Background()
+	legacy := bson.M{"action": bson.M{"$in": bson.A{models.ReactionLike, models.ReactionDislike}}}
+
+	cursor, err := ac.InteractionCol.Find(ctx, legacy, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var interaction models.AudiobookInteraction
+		if err := cursor.Decode(&interaction); err != nil {
+			continue
+		}
+		_, err := ac.ReactionCol.UpdateOne(ctx,
+			bson.M{"audiobookId": interaction.AudiobookID, "userId": interaction.UserID},
+			bson.M{
+				"$set":         bson.M{"reaction": interaction.Action, "updatedAt": interaction.CreatedAt},
+				"$setOnInsert": bson.M{"createdAt": interaction.CreatedAt},
+			},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = ac.InteractionCol.DeleteMany(ctx, legacy)
+	return err
+}
+
+// reconcileReactionCounts sets likes/dislikes from the reaction documents wherever they drifted.
+// The stored counters are read before the reactions are counted, and each correction only applies
+// if the counters are still unchanged, so a reaction landing in between is never overwritten.
+func (ac *AudiobookController) reconcileReactionCounts() error {
+	ctx := context.Background()
+
+	type counters struct{ likes, dislikes int }
+	stored := map[primitive.ObjectID]counters{}
+	cursor, err := ac.AudiobookCol.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"likes": 1, "dislikes": 1}))
+	if err != nil {
+		return err
+	}
+	for cursor.Next(ctx) {
+		var audiobook models.Audiobook
+		if err := cursor.Decode(&audiobook); err == nil {
+			stored[audiobook.ID] = counters{audiobook.Likes, audiobook.Dislikes}
+		}
+	}
+	cursor.Close(ctx)
+
+	cursor, err = ac.ReactionCol.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"audiobookId": "$audiobookId", "reaction": "$reaction"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		ID struct {
+			AudiobookID primitive.ObjectID `bson:"audiobookId"`
+			Reaction    string             `bson:"reaction"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+	counts := map[primitive.ObjectID]map[string]int{}
+	for _, g := range groups {
+		if counts[g.ID.AudiobookID] == nil {
+			counts[g.ID.AudiobookID] = map[string]int{}
+		}
+		counts[g.ID.AudiobookID][reactionCounter(g.ID.Reaction)] = g.Count
+	}
+
+	var writes []mongo.WriteModel
+	for id, current := range stored {
+		likes := counts[id][reactionCounter(models.ReactionLike)]
+		dislikes := counts[id][reactionCounter(models.ReactionDislike)]
+		if current.likes != likes || current.dislikes != dislikes {
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": id, "likes": current.likes, "dislikes": current.dislikes}).
+				SetUpdate(bson.M{"$set": bson.M{"likes": likes, "dislikes": dislikes}}))
+		}
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	if _, err := ac.AudiobookCol.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return err
+	}
+	log.Printf("Reaction reconciliation corrected %d audiobooks", len(writes))
+	return nil
+}
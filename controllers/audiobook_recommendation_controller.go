@@ -0,0 +1,310 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	models "live_stream/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxRecommendations = 50
+
+	// Relative weight of each recommendation source
+	relatedToLikedWeight  = 1.0
+	followedAuthorWeight  = 0.8
+	genreAffinityWeight   = 0.5
+	genreCandidatesPerRun = 100
+)
+
+// Recommendation is one suggested audiobook together with why it was suggested
+type Recommendation struct {
+	Audiobook models.Audiobook `json:"audiobook"`
+	Score     float64          `json:"score"`
+	Reason    string           `json:"reason"`
+}
+
+type recommendationCandidate struct {
+	score      float64
+	reason     string
+	reasonBest float64
+}
+
+func (rc *recommendationCandidate) add(score float64, reason string) {
+	rc.score += score
+	if score > rc.reasonBest {
+		rc.reasonBest = score
+		rc.reason = reason
+	}
+}
+
+// GetRecommendations - authenticated "For you" feed. Combines titles related to the user's likes,
+// new titles from followed authors and the user's genre affinity, falling back to trending titles
+// for new users. Each suggestion carries a human readable reason.
+func (ac *AudiobookController) GetRecommendations(c *gin.Context) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > maxRecommendations {
+		limit = 20
+	}
+
+	ctx := context.TODO()
+
+	var user models.User
+	if err := ac.UserCol.FindOne(ctx, bson.M{"_id": uid}).Decode(&user); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	// The user's history: what they reacted to and listened to
+	cursor, err := ac.ReactionCol.Find(ctx, bson.M{"userId": uid})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch history"})
+		return
+	}
+	var reactions []models.AudiobookReaction
+	cursor.All(ctx, &reactions)
+
+	cursor, err = ac.InteractionCol.Find(ctx, bson.M{"userId": uid})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch history"})
+		return
+	}
+	var interactions []models.AudiobookInteraction
+	cursor.All(ctx, &interactions)
+
+	seen := map[primitive.ObjectID]bool{}
+	disliked := map[primitive.ObjectID]bool{}
+	var liked, engaged []primitive.ObjectID
+	markSeen := func(id primitive.ObjectID) {
+		if !seen[id] {
+			seen[id] = true
+			engaged = append(engaged, id)
+		}
+	}
+	for _, r := range reactions {
+		markSeen(r.AudiobookID)
+		switch r.Reaction {
+		case models.ReactionLike:
+			liked = append(liked, r.AudiobookID)
+		case models.ReactionDislike:
+			disliked[r.AudiobookID] = true
+		}
+	}
+	for _, in := range interactions {
+		markSeen(in.AudiobookID)
+	}
+
+	candidates := map[primitive.ObjectID]*recommendationCandidate{}
+	candidate := func(id primitive.ObjectID) *recommendationCandidate {
+		if candidates[id] == nil {
+			candidates[id] = &recommendationCandidate{}
+		}
+		return candidates[id]
+	}
+
+	names := ac.audiobookNames(ctx, engaged)
+
+	// 1. Titles related to the ones the user liked
+	if len(liked) > 0 {
+		cursor, err := ac.RelatedCol.Find(ctx, bson.M{"_id": bson.M{"$in": liked}})
+		if err == nil {
+			var lists []models.RelatedAudiobooks
+			cursor.All(ctx, &lists)
+			for _, list := range lists {
+				for _, r := range list.Related {
+					if !seen[r.AudiobookID] {
+						candidate(r.AudiobookID).add(relatedToLikedWeight*r.Score, "Because you liked "+names[list.AudiobookID])
+					}
+				}
+			}
+		}
+	}
+
+	filter := loadContentPreferences(c, ac.UserCol).apply(publishedFilter(time.Now()))
+	filter["_id"] = bson.M{"$nin": engaged}
+
+	// 2. Titles by followed authors
+	if len(user.FollowedAuthors) > 0 {
+		authorFilter := copyFilter(filter)
+		authorFilter["authors"] = bson.M{"$in": user.FollowedAuthors}
+		followed := map[string]bool{}
+		for _, a := range user.FollowedAuthors {
+			followed[a] = true
+		}
+
+		cursor, err := ac.AudiobookCol.Find(ctx, authorFilter,
+			options.Find().SetProjection(bson.M{"authors": 1}).SetSort(bson.M{"publishedAt": -1}).SetLimit(genreCandidatesPerRun),
+		)
+		if err == nil {
+			var byAuthors []models.Audiobook
+			cursor.All(ctx, &byAuthors)
+			for _, a := range byAuthors {
+				for _, author := range a.Authors {
+					if followed[author] {
+						candidate(a.ID).add(followedAuthorWeight, "From "+author+", who you follow")
+						break
+					}
+				}
+			}
+		}
+	}
+
+	// 3. Genre affinity from the user's history, leaving out what they disliked
+	enjoyed := make([]primitive.ObjectID, 0, len(engaged))
+	for _, id := range engaged {
+		if !disliked[id] {
+			enjoyed = append(enjoyed, id)
+		}
+	}
+	affinity := ac.genreAffinity(ctx, enjoyed, liked)
+	if len(affinity) > 0 {
+		genres := make([]string, 0, len(affinity))
+		for g := range affinity {
+			genres = append(genres, g)
+		}
+		genreFilter := copyFilter(filter)
+		genreFilter["genres"] = bson.M{"$in": genres}
+
+		cursor, err := ac.AudiobookCol.Find(ctx, genreFilter,
+			options.Find().SetProjection(bson.M{"genres": 1}).SetSort(bson.M{"likes": -1}).SetLimit(genreCandidatesPerRun),
+		)
+		if err == nil {
+			var byGenre []models.Audiobook
+			cursor.All(ctx, &byGenre)
+			for _, a := range byGenre {
+				for _, g := range a.Genres {
+					if w, ok := affinity[g]; ok {
+						candidate(a.ID).add(genreAffinityWeight*w, "Because you listen to "+g)
+					}
+				}
+			}
+		}
+	}
+
+	// Rank, then load the audiobooks that pass the published and content filters
+	ranked := make([]primitive.ObjectID, 0, len(candidates))
+	for id := range candidates {
+		ranked = append(ranked, id)
+	}
+	sort.Slice(ranked, func(i, j int) bool { return candidates[ranked[i]].score > candidates[ranked[j]].score })
+
+	recommendations := []Recommendation{}
+	if len(ranked) > 0 {
+		rankedFilter := copyFilter(filter)
+		rankedFilter["_id"] = bson.M{"$in": ranked, "$nin": engaged}
+		cursor, err := ac.AudiobookCol.Find(ctx, rankedFilter, options.Find().SetProjection(bson.M{"audioData": 0}))
+		if err == nil {
+			var found []models.Audiobook
+			cursor.All(ctx, &found)
+			byID := make(map[primitive.ObjectID]models.Audiobook, len(found))
+			for _, a := range found {
+				byID[a.ID] = a
+			}
+			for _, id := range ranked {
+				if a, ok := byID[id]; ok && len(recommendations) < limit {
+					recommendations = append(recommendations, Recommendation{Audiobook: a, Score: candidates[id].score, Reason: candidates[id].reason})
+				}
+			}
+		}
+	}
+
+	// Cold start, or not enough personal signal: fill up with trending titles
+	if len(recommendations) < limit {
+		exclude := append([]primitive.ObjectID{}, engaged...)
+		for _, r := range recommendations {
+			exclude = append(exclude, r.Audiobook.ID)
+		}
+		trendingFilter := copyFilter(filter)
+		trendingFilter["_id"] = bson.M{"$nin": exclude}
+		for _, a := range ac.trendingAudiobooks(ctx, "weekly", trendingFilter, limit-len(recommendations)) {
+			recommendations = append(recommendations, Recommendation{Audiobook: a, Reason: "Trending now"})
+		}
+	}
+
+	langs := preferredLanguages(c)
+	for i := range recommendations {
+		localizeAudiobook(&recommendations[i].Audiobook, langs)
+	}
+	c.Header("Vary", "Accept-Language")
+	c.JSON(http.StatusOK, recommendations)
+}
+
+// genreAffinity weighs the genres of the titles the user engaged with (likes count double),
+// normalized to 0..1. Callers leave disliked titles out of engaged.
+func (ac *AudiobookController) genreAffinity(ctx context.Context, engaged, liked []primitive.ObjectID) map[string]float64 {
+	affinity := map[string]float64{}
+	if len(engaged) == 0 {
+		return affinity
+	}
+
+	likedSet := map[primitive.ObjectID]bool{}
+	for _, id := range liked {
+		likedSet[id] = true
+	}
+
+	cursor, err := ac.AudiobookCol.Find(ctx, bson.M{"_id": bson.M{"$in": engaged}}, options.Find().SetProjection(bson.M{"genres": 1}))
+	if err != nil {
+		return affinity
+	}
+	var history []models.Audiobook
+	cursor.All(ctx, &history)
+
+	max := 0.0
+	for _, a := range history {
+		weight := 1.0
+		if likedSet[a.ID] {
+			weight = 2
+		}
+		for _, g := range a.Genres {
+			affinity[g] += weight
+			if affinity[g] > max {
+				max = affinity[g]
+			}
+		}
+	}
+	for g := range affinity {
+		affinity[g] /= max
+	}
+	return affinity
+}
+
+// audiobookNames maps audiobook IDs to their names, for explanations
+func (ac *AudiobookController) audiobookNames(ctx context.Context, ids []primitive.ObjectID) map[primitive.ObjectID]string {
+	names := map[primitive.ObjectID]string{}
+	if len(ids) == 0 {
+		return names
+	}
+	cursor, err := ac.AudiobookCol.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"name": 1}))
+	if err != nil {
+		return names
+	}
+	var audiobooks []models.Audiobook
+	cursor.All(ctx, &audiobooks)
+	for _, a := range audiobooks {
+		names[a.ID] = a.Name
+	}
+	return names
+}
+
+// copyFilter makes a shallow copy of a query so sources can add their own conditions
+func copyFilter(filter bson.M) bson.M {
+	copied := make(bson.M, len(filter))
+	for k, v := range filter {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,300 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	models "live_stream/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxRelatedAudiobooks = 20
+	// Users with a very long history add little signal and make pair counting quadratic
+	maxInteractionsPerUser = 200
+
+	coEngagementWeight = 0.7
+	metadataWeight     = 0.3
+)
+
+// Weight of each interaction towards "this user enjoyed this title"
+var interactionWeights = map[string]float64{
+	"like": 2,
+	"view": 1,
+}
+
+// GetRelatedAudiobooks - public endpoint listing audiobooks related to the given one (by ID or slug).
+// Falls back to titles sharing a genre when nothing has been computed yet.
+func (ac *AudiobookController) GetRelatedAudiobooks(c *gin.Context) {
+	objID, ok := resolveAudiobookParam(c, ac.SlugCol)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 || limit > maxRelatedAudiobooks {
+		limit = 10
+	}
+
+	ctx := context.TODO()
+	filter := loadContentPreferences(c, ac.UserCol).apply(publishedFilter(time.Now()))
+	projection := options.Find().SetProjection(bson.M{"audioData": 0})
+
+	var audiobooks []models.Audiobook
+	var related models.RelatedAudiobooks
+	if err := ac.RelatedCol.FindOne(ctx, bson.M{"_id": objID}).Decode(&related); err == nil && len(related.Related) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(related.Related))
+		for _, r := range related.Related {
+			ids = append(ids, r.AudiobookID)
+		}
+		filter["_id"] = bson.M{"$in": ids}
+
+		cursor, err := ac.AudiobookCol.Find(ctx, filter, projection)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related audiobooks"})
+			return
+		}
+		var found []models.Audiobook
+		cursor.All(ctx, &found)
+
+		byID := make(map[primitive.ObjectID]models.Audiobook, len(found))
+		for _, a := range found {
+			byID[a.ID] = a
+		}
+		for _, id := range ids {
+			if a, ok := byID[id]; ok && len(audiobooks) < limit {
+				audiobooks = append(audiobooks, a)
+			}
+		}
+	} else {
+		var source models.Audiobook
+		if err := ac.AudiobookCol.FindOne(ctx, bson.M{"_id": objID}).Decode(&source); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+			return
+		}
+		filter["_id"] = bson.M{"$ne": objID}
+		if len(source.Genres) > 0 {
+			filter["genres"] = bson.M{"$in": source.Genres}
+		}
+
+		cursor, err := ac.AudiobookCol.Find(ctx, filter,
+			projection.SetSort(bson.M{"likes": -1}).SetLimit(int64(limit)),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related audiobooks"})
+			return
+		}
+		cursor.All(ctx, &audiobooks)
+	}
+
+	if audiobooks == nil {
+		audiobooks = []models.Audiobook{}
+	}
+	prefs := preferredLanguages(c)
+	for i := range audiobooks {
+		localizeAudiobook(&audiobooks[i], prefs)
+	}
+	c.Header("Vary", "Accept-Language")
+	c.JSON(http.StatusOK, audiobooks)
+}
+
+// RunRelatedTitlesJob periodically recomputes related audiobooks.
+// It blocks, so start it in its own goroutine.
+func (ac *AudiobookController) RunRelatedTitlesJob(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := ac.computeRelatedAudiobooks(); err != nil {
+			log.Println("Related titles job failed:", err)
+		}
+		<-ticker.C
+	}
+}
+
+type pairKey struct{ a, b primitive.ObjectID }
+
+// computeRelatedAudiobooks scores every pair of published audiobooks by how often the same
+// users engaged with both (cosine similarity over weighted interactions) blended with
+// metadata similarity (shared genres and language), and stores the best matches for each title.
+func (ac *AudiobookController) computeRelatedAudiobooks() error {
+	ctx := context.Background()
+	now := time.Now()
+
+	cursor, err := ac.AudiobookCol.Find(ctx, publishedFilter(now),
+		options.Find().SetProjection(bson.M{"_id": 1, "genres": 1, "language": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var catalog []models.Audiobook
+	if err := cursor.All(ctx, &catalog); err != nil {
+		return err
+	}
+	published := make(map[primitive.ObjectID]bool, len(catalog))
+	for _, a := range catalog {
+		published[a.ID] = true
+	}
+
+	// Weighted engagement per user: likes from reactions, listens from interactions
+	userItems := map[primitive.ObjectID]map[primitive.ObjectID]float64{}
+	addEngagement := func(userID, audiobookID primitive.ObjectID, weight float64) {
+		if !published[audiobookID] {
+			return
+		}
+		items := userItems[userID]
+		if items == nil {
+			items = map[primitive.ObjectID]float64{}
+			userItems[userID] = items
+		}
+		if len(items) < maxInteractionsPerUser {
+			items[audiobookID] = math.Max(items[audiobookID], weight)
+		}
+	}
+
+	cursor, err = ac.ReactionCol.Find(ctx,
+		bson.M{"reaction": models.ReactionLike},
+		options.Find().SetProjection(bson.M{"userId": 1, "audiobookId": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	for cursor.Next(ctx) {
+		var reaction models.AudiobookReaction
+		if err := cursor.Decode(&reaction); err == nil {
+			addEngagement(reaction.UserID, reaction.AudiobookID, interactionWeights["like"])
+		}
+	}
+	cursor.Close(ctx)
+
+	cursor, err = ac.InteractionCol.Find(ctx,
+		bson.M{"action": "view"},
+		options.Find().SetProjection(bson.M{"userId": 1, "audiobookId": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	for cursor.Next(ctx) {
+		var interaction models.AudiobookInteraction
+		if err := cursor.Decode(&interaction); err == nil {
+			addEngagement(interaction.UserID, interaction.AudiobookID, interactionWeights["view"])
+		}
+	}
+	cursor.Close(ctx)
+
+	norms := map[primitive.ObjectID]float64{}
+	dots := map[pairKey]float64{}
+	for _, items := range userItems {
+		ids := make([]primitive.ObjectID, 0, len(items))
+		for id, w := range items {
+			ids = append(ids, id)
+			norms[id] += w * w
+		}
+		for i := range ids {
+			for j := i + 1; j < len(ids); j++ {
+				a, b := ids[i], ids[j]
+				if b.Hex() < a.Hex() {
+					a, b = b, a
+				}
+				dots[pairKey{a, b}] += items[a] * items[b]
+			}
+		}
+	}
+
+	scores := map[primitive.ObjectID]map[primitive.ObjectID]float64{}
+	addScore := func(a, b primitive.ObjectID, s float64) {
+		if scores[a] == nil {
+			scores[a] = map[primitive.ObjectID]float64{}
+		}
+		scores[a][b] += s
+	}
+	for pair, dot := range dots {
+		cosine := dot / math.Sqrt(norms[pair.a]*norms[pair.b])
+		addScore(pair.a, pair.b, coEngagementWeight*cosine)
+		addScore(pair.b, pair.a, coEngagementWeight*cosine)
+	}
+
+	// Only titles sharing a genre are compared, instead of every pair in the catalog
+	byGenre := map[string][]int{}
+	for i, a := range catalog {
+		for _, g := range a.Genres {
+			byGenre[g] = append(byGenre[g], i)
+		}
+	}
+	compared := map[pairKey]bool{}
+	for _, members := range byGenre {
+		for x := range members {
+			for y := x + 1; y < len(members); y++ {
+				a, b := catalog[members[x]], catalog[members[y]]
+				key := pairKey{a.ID, b.ID}
+				if b.ID.Hex() < a.ID.Hex() {
+					key = pairKey{b.ID, a.ID}
+				}
+				if compared[key] {
+					continue
+				}
+				compared[key] = true
+				if sim := metadataSimilarity(a, b); sim > 0 {
+					addScore(a.ID, b.ID, metadataWeight*sim)
+					addScore(b.ID, a.ID, metadataWeight*sim)
+				}
+			}
+		}
+	}
+
+	for _, a := range catalog {
+		related := make([]models.RelatedAudiobook, 0, len(scores[a.ID]))
+		for id, score := range scores[a.ID] {
+			related = append(related, models.RelatedAudiobook{AudiobookID: id, Score: score})
+		}
+		sort.Slice(related, func(i, j int) bool { return related[i].Score > related[j].Score })
+		if len(related) > maxRelatedAudiobooks {
+			related = related[:maxRelatedAudiobooks]
+		}
+
+		_, err := ac.RelatedCol.ReplaceOne(ctx,
+			bson.M{"_id": a.ID},
+			models.RelatedAudiobooks{AudiobookID: a.ID, Related: related, ComputedAt: now},
+			options.Replace().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Titles no longer published should not keep stale lists around
+	_, err = ac.RelatedCol.DeleteMany(ctx, bson.M{"computedAt": bson.M{"$lt": now}})
+	return err
+}
+
+// metadataSimilarity is the Jaccard similarity of the genres, plus a small bonus for a shared language.
+// Titles without a genre in common are unrelated, whatever their language.
+func metadataSimilarity(a, b models.Audiobook) float64 {
+	shared := 0
+	for _, g := range a.Genres {
+		for _, h := range b.Genres {
+			if g == h {
+				shared++
+			}
+		}
+	}
+	if shared == 0 {
+		return 0
+	}
+	sim := float64(shared) / float64(len(a.Genres)+len(b.Genres)-shared)
+	if a.Language != "" && a.Language == b.Language {
+		sim = 0.8*sim + 0.2
+	} else {
+		sim = 0.8 * sim
+	}
+	return sim
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"math"
+	"testing"
+
+	models "live_stream/models"
+)
+
+func TestMetadataSimilarity(t *testing.T) {
+	book := func(language string, genres ...string) models.Audiobook {
+		return models.Audiobook{Language: language, Genres: genres}
+	}
+
+	tests := []struct {
+		name string
+		a, b models.Audiobook
+		want float64
+	}{
+		{"same genres and language", book("hi", "classic"), book("hi", "classic"), 1},
+		{"same genres, other language", book("hi", "classic"), book("en", "classic"), 0.8},
+		{"half the genres shared", book("", "classic", "drama"), book("", "classic"), 0.4},
+		{"one in three shared, same language", book("hi", "classic", "drama"), book("hi", "classic", "poetry"), 0.8/3 + 0.2},
+		{"no shared genre, same language", book("hi", "classic"), book("hi", "poetry"), 0},
+		{"no genres", book("hi"), book("hi"), 0},
+		{"empty language is not shared", book("", "classic"), book("", "classic"), 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := metadataSimilarity(tt.a, tt.b)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("metadataSimilarity() = %v, want %v", got, tt.want)
+			}
+			if back := metadataSimilarity(tt.b, tt.a); math.Abs(back-got) > 1e-9 {
+				t.Errorf("not symmetric: %v vs %v", got, back)
+			}
+		})
+	}
+}
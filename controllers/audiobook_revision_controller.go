@@ -0,0 +1,344 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	models "live_stream/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FieldChange describes a single metadata field that differs between two revisions
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Attempts at taking the next revision number when concurrent edits race for it
+const maxRevisionAttempts = 5
+
+// Media values up to this length (paths, URLs, predefined names) are kept in revisions as is;
+// longer ones are inline data and only their hash is kept
+const maxMediaRefLength = 2048
+
+func mediaSnapshotOf(value string) models.MediaSnapshot {
+	if value == "" {
+		return models.MediaSnapshot{}
+	}
+	sum := sha256.Sum256([]byte(value))
+	snapshot := models.MediaSnapshot{Hash: hex.EncodeToString(sum[:])}
+	if len(value) <= maxMediaRefLength {
+		snapshot.Ref = value
+	}
+	return snapshot
+}
+
+// describe shows a media snapshot in a diff: the reference when there is one, else the hash
+func describeMedia(m models.MediaSnapshot) string {
+	if m.Ref != "" || m.Hash == "" {
+		return m.Ref
+	}
+	return "sha256:" + m.Hash
+}
+
+func metadataOf(a models.Audiobook) models.AudiobookMetadata {
+	return models.AudiobookMetadata{
+		Name:            a.Name,
+		Description:     a.Description,
+		Audio:           mediaSnapshotOf(a.AudioData),
+		Thumbnail:       mediaSnapshotOf(a.Thumbnail),
+		Content:         a.Content,
+		Language:        a.Language,
+		Translations:    a.Translations,
+		Authors:         a.Authors,
+		Genres:          a.Genres,
+		AgeRating:       a.AgeRating,
+		ContentWarnings: a.ContentWarnings,
+	}
+}
+
+// restoreMedia returns the value to restore a media snapshot from, if it can be restored:
+// the snapshot's reference, when its hash matches, or nothing for the empty value
+func restoreMedia(m models.MediaSnapshot) (string, bool) {
+	if m.Hash == "" {
+		return "", true
+	}
+	if m.Ref != "" && mediaSnapshotOf(m.Ref).Hash == m.Hash {
+		return m.Ref, true
+	}
+	return "", false
+}
+
+// metadataUpdate builds the update restoring a revision's metadata onto the current audiobook.
+// Empty catalog fields are removed, as they are omitted on the audiobook itself. Inline media that
+// changed since the revision can't be restored from its hash; it is kept and its field returned.
+func metadataUpdate(m models.AudiobookMetadata, current models.Audiobook, now time.Time) (bson.M, []string) {
+	set := bson.M{
+		"name":        m.Name,
+		"description": m.Description,
+		"content":     m.Content,
+		"updatedAt":   now,
+	}
+	unset := bson.M{}
+	unrestored := []string{}
+
+	media := []struct {
+		field    string
+		snapshot models.MediaSnapshot
+		current  string
+	}{
+		{"audioData", m.Audio, current.AudioData},
+		{"thumbnail", m.Thumbnail, current.Thumbnail},
+	}
+	for _, f := range media {
+		if mediaSnapshotOf(f.current).Hash == f.snapshot.Hash {
+			continue
+		}
+		if value, ok := restoreMedia(f.snapshot); ok {
+			set[f.field] = value
+		} else {
+			unrestored = append(unrestored, f.field)
+		}
+	}
+
+	restore := func(field string, value interface{}, empty bool) {
+		if empty {
+			unset[field] = ""
+		} else {
+			set[field] = value
+		}
+	}
+	restore("language", m.Language, m.Language == "")
+	restore("translations", m.Translations, len(m.Translations) == 0)
+	restore("authors", m.Authors, len(m.Authors) == 0)
+	restore("genres", m.Genres, len(m.Genres) == 0)
+	restore("ageRating", m.AgeRating, m.AgeRating == "")
+	restore("contentWarnings", m.ContentWarnings, len(m.ContentWarnings) == 0)
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update, unrestored
+}
+
+func diffMetadata(from, to models.AudiobookMetadata) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field, a, b string) {
+		if a != b {
+			changes = append(changes, FieldChange{Field: field, From: a, To: b})
+		}
+	}
+	add("name", from.Name, to.Name)
+	add("description", from.Description, to.Description)
+	if from.Audio.Hash != to.Audio.Hash {
+		add("audioData", describeMedia(from.Audio), describeMedia(to.Audio))
+	}
+	if from.Thumbnail.Hash != to.Thumbnail.Hash {
+		add("thumbnail", describeMedia(from.Thumbnail), describeMedia(to.Thumbnail))
+	}
+	add("content", from.Content, to.Content)
+	add("language", from.Language, to.Language)
+	add("authors", strings.Join(from.Authors, ", "), strings.Join(to.Authors, ", "))
+	add("genres", strings.Join(from.Genres, ", "), strings.Join(to.Genres, ", "))
+	add("ageRating", from.AgeRating, to.AgeRating)
+	add("contentWarnings", strings.Join(from.ContentWarnings, ", "), strings.Join(to.ContentWarnings, ", "))
+
+	locales := []string{}
+	for locale := range from.Translations {
+		locales = append(locales, locale)
+	}
+	for locale := range to.Translations {
+		if _, ok := from.Translations[locale]; !ok {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		a, b := from.Translations[locale], to.Translations[locale]
+		prefix := "translations." + locale + "."
+		add(prefix+"name", a.Name, b.Name)
+		add(prefix+"description", a.Description, b.Description)
+		add(prefix+"content", a.Content, b.Content)
+	}
+	return changes
+}
+
+// recordRevision stores a new revision snapshot of the audiobook's metadata.
+// (audiobookId, revision) is unique, so when a concurrent edit takes the same number it retries with the next one.
+func (ac *AudiobookController) recordRevision(audiobookID primitive.ObjectID, metadata models.AudiobookMetadata, editedBy *primitive.ObjectID, rolledBackFrom int) error {
+	ctx := context.TODO()
+
+	var err error
+	for attempt := 0; attempt < maxRevisionAttempts; attempt++ {
+		next := 1
+		var last models.AudiobookRevision
+		err = ac.RevisionCol.FindOne(ctx,
+			bson.M{"audiobookId": audiobookID},
+			options.FindOne().SetSort(bson.M{"revision": -1}),
+		).Decode(&last)
+		if err == nil {
+			next = last.Revision + 1
+		} else if err != mongo.ErrNoDocuments {
+			return err
+		}
+
+		_, err = ac.RevisionCol.InsertOne(ctx, models.AudiobookRevision{
+			AudiobookID:    audiobookID,
+			Revision:       next,
+			Metadata:       metadata,
+			EditedBy:       editedBy,
+			RolledBackFrom: rolledBackFrom,
+			CreatedAt:      time.Now(),
+		})
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// ensureBaselineRevision snapshots an audiobook created before revision tracking,
+// so its original values are not lost on the first edit
+func (ac *AudiobookController) ensureBaselineRevision(audiobookID primitive.ObjectID) error {
+	count, err := ac.RevisionCol.CountDocuments(context.TODO(), bson.M{"audiobookId": audiobookID})
+	if err != nil || count > 0 {
+		return err
+	}
+
+	var audiobook models.Audiobook
+	if err := ac.AudiobookCol.FindOne(context.TODO(), bson.M{"_id": audiobookID}).Decode(&audiobook); err != nil {
+		return err
+	}
+	return ac.recordRevision(audiobookID, metadataOf(audiobook), nil, 0)
+}
+
+func (ac *AudiobookController) findRevision(audiobookID primitive.ObjectID, revision string) (models.AudiobookRevision, error) {
+	var rev models.AudiobookRevision
+	number, err := strconv.Atoi(revision)
+	if err != nil {
+		return rev, err
+	}
+	err = ac.RevisionCol.FindOne(context.TODO(), bson.M{"audiobookId": audiobookID, "revision": number}).Decode(&rev)
+	return rev, err
+}
+
+// GetAudiobookRevisions - admin endpoint to list all revisions of an audiobook, newest first
+func (ac *AudiobookController) GetAudiobookRevisions(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	cursor, err := ac.RevisionCol.Find(context.TODO(),
+		bson.M{"audiobookId": objID},
+		options.Find().SetSort(bson.M{"revision": -1}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+	revisions := []models.AudiobookRevision{}
+	cursor.All(context.TODO(), &revisions)
+	c.JSON(http.StatusOK, revisions)
+}
+
+// DiffAudiobookRevisions - admin endpoint to compare two revisions (?from=1&to=3)
+func (ac *AudiobookController) DiffAudiobookRevisions(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	from, err := ac.findRevision(objID, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision 'from' not found"})
+		return
+	}
+	to, err := ac.findRevision(objID, c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision 'to' not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":    from.Revision,
+		"to":      to.Revision,
+		"changes": diffMetadata(from.Metadata, to.Metadata),
+	})
+}
+
+// RollbackAudiobook - admin endpoint to restore the metadata of a previous revision.
+// The rollback itself is recorded as a new revision. Revisions only keep a hash of inline media,
+// so changed inline audio or thumbnails are left as they are and listed in mediaNotRestored.
+func (ac *AudiobookController) RollbackAudiobook(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	adminID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	rev, err := ac.findRevision(objID, c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+
+	filter := bson.M{"_id": objID, "deletedAt": nil}
+	var current models.Audiobook
+	if err := ac.AudiobookCol.FindOne(context.TODO(), filter).Decode(&current); err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to roll back audiobook"})
+		return
+	}
+	update, unrestored := metadataUpdate(rev.Metadata, current, time.Now())
+
+	// The update is worked out against the current media, so it only applies if nothing changed meanwhile
+	filter["updatedAt"] = current.UpdatedAt
+	var updated models.Audiobook
+	err = ac.AudiobookCol.FindOneAndUpdate(
+		context.TODO(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusConflict, gin.H{"error": "Audiobook was changed concurrently, please retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to roll back audiobook"})
+		return
+	}
+
+	// Snapshot the result, which keeps media that couldn't be restored
+	if err := ac.recordRevision(objID, metadataOf(updated), &adminID, rev.Revision); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Audiobook rolled back but failed to record revision"})
+		return
+	}
+	ac.updateAudiobookSlug(objID, rev.Metadata.Name)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Audiobook rolled back", "revision": rev.Revision, "mediaNotRestored": unrestored})
+}
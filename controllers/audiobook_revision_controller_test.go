@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	models "live_stream/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMediaSnapshotOf(t *testing.T) {
+	inline := strings.Repeat("QUJD", maxMediaRefLength)
+
+	tests := []struct {
+		name    string
+		value   string
+		wantRef string
+		hashed  bool
+	}{
+		{"empty", "", "", false},
+		{"path", "imports/ext-1/book.mp3", "imports/ext-1/book.mp3", true},
+		{"url", "https://cdn.example.com/a.mp3", "https://cdn.example.com/a.mp3", true},
+		{"inline data", inline, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mediaSnapshotOf(tt.value)
+			if got.Ref != tt.wantRef {
+				t.Errorf("Ref = %q, want %q", got.Ref, tt.wantRef)
+			}
+			if (got.Hash != "") != tt.hashed {
+				t.Errorf("Hash = %q, hashed = %v", got.Hash, tt.hashed)
+			}
+		})
+	}
+
+	if mediaSnapshotOf("a.mp3").Hash == mediaSnapshotOf("b.mp3").Hash {
+		t.Error("different values share a hash")
+	}
+}
+
+func TestRestoreMedia(t *testing.T) {
+	tests := []struct {
+		name      string
+		snapshot  models.MediaSnapshot
+		wantValue string
+		wantOK    bool
+	}{
+		{"empty", models.MediaSnapshot{}, "", true},
+		{"reference", mediaSnapshotOf("book.mp3"), "book.mp3", true},
+		{"inline data", models.MediaSnapshot{Hash: mediaSnapshotOf("data").Hash}, "", false},
+		{"reference not matching its hash", models.MediaSnapshot{Hash: mediaSnapshotOf("a.mp3").Hash, Ref: "b.mp3"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := restoreMedia(tt.snapshot)
+			if value != tt.wantValue || ok != tt.wantOK {
+				t.Errorf("restoreMedia() = %q, %v, want %q, %v", value, ok, tt.wantValue, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDiffMetadata(t *testing.T) {
+	base := models.Audiobook{
+		Name:         "Godaan",
+		Description:  "A novel",
+		AudioData:    "godaan.mp3",
+		Language:     "hi",
+		Authors:      []string{"Premchand"},
+		Genres:       []string{"classic"},
+		Translations: map[string]models.AudiobookTranslation{"en": {Name: "The Gift of a Cow"}},
+	}
+
+	tests := []struct {
+		name   string
+		change func(a *models.Audiobook)
+		want   []FieldChange
+	}{
+		{"unchanged", func(a *models.Audiobook) {}, []FieldChange{}},
+		{"name", func(a *models.Audiobook) { a.Name = "Gaban" }, []FieldChange{{"name", "Godaan", "Gaban"}}},
+		{"audio reference", func(a *models.Audiobook) { a.AudioData = "v2.mp3" }, []FieldChange{{"audioData", "godaan.mp3", "v2.mp3"}}},
+		{"genres", func(a *models.Audiobook) { a.Genres = []string{"classic", "drama"} }, []FieldChange{{"genres", "classic", "classic, drama"}}},
+		{"language", func(a *models.Audiobook) { a.Language = "" }, []FieldChange{{"language", "hi", ""}}},
+		{
+			"translation added",
+			func(a *models.Audiobook) {
+				a.Translations = map[string]models.AudiobookTranslation{"en": {Name: "The Gift of a Cow"}, "fr": {Name: "Le Don"}}
+			},
+			[]FieldChange{{"translations.fr.name", "", "Le Don"}},
+		},
+		{
+			"translation removed",
+			func(a *models.Audiobook) { a.Translations = nil },
+			[]FieldChange{{"translations.en.name", "The Gift of a Cow", ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base
+			tt.change(&changed)
+			got := diffMetadata(metadataOf(base), metadataOf(changed))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffMetadataInlineAudio(t *testing.T) {
+	from := metadataOf(models.Audiobook{AudioData: strings.Repeat("A", maxMediaRefLength+1)})
+	to := metadataOf(models.Audiobook{AudioData: strings.Repeat("B", maxMediaRefLength+1)})
+
+	got := diffMetadata(from, to)
+	if len(got) != 1 || got[0].Field != "audioData" {
+		t.Fatalf("diffMetadata() = %v, want one audioData change", got)
+	}
+	if !strings.HasPrefix(got[0].From, "sha256:") || !strings.HasPrefix(got[0].To, "sha256:") {
+		t.Errorf("inline audio should be shown by hash, got %q -> %q", got[0].From, got[0].To)
+	}
+}
+
+func TestMetadataUpdate(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	inline := strings.Repeat("A", maxMediaRefLength+1)
+
+	tests := []struct {
+		name           string
+		revision       models.Audiobook
+		current        models.Audiobook
+		wantSet        bson.M
+		wantUnset      bson.M
+		wantUnrestored []string
+	}{
+		{
+			name:     "catalog fields restored and cleared",
+			revision: models.Audiobook{Name: "Old", Genres: []string{"classic"}},
+			current:  models.Audiobook{Name: "New", Language: "hi"},
+			wantSet: bson.M{
+				"name": "Old", "description": "", "content": "", "updatedAt": now,
+				"genres": []string{"classic"},
+			},
+			wantUnset: bson.M{
+				"language": "", "translations": "", "authors": "", "ageRating": "", "contentWarnings": "",
+			},
+			wantUnrestored: []string{},
+		},
+		{
+			name:           "referenced media restored",
+			revision:       models.Audiobook{AudioData: "old.mp3", Thumbnail: "thumb-1"},
+			current:        models.Audiobook{AudioData: "new.mp3", Thumbnail: "thumb-1"},
+			wantSet:        bson.M{"audioData": "old.mp3"},
+			wantUnrestored: []string{},
+		},
+		{
+			name:           "unchanged inline media left alone",
+			revision:       models.Audiobook{AudioData: inline},
+			current:        models.Audiobook{AudioData: inline},
+			wantUnrestored: []string{},
+		},
+		{
+			name:           "changed inline media not restored",
+			revision:       models.Audiobook{AudioData: inline},
+			current:        models.Audiobook{AudioData: "new.mp3"},
+			wantUnrestored: []string{"audioData"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update, unrestored := metadataUpdate(metadataOf(tt.revision), tt.current, now)
+			set, _ := update["$set"].(bson.M)
+			for field, want := range tt.wantSet {
+				if got, ok := set[field]; !ok || !reflect.DeepEqual(got, want) {
+					t.Errorf("$set[%q] = %v, want %v", field, got, want)
+				}
+			}
+			if tt.wantUnset != nil && !reflect.DeepEqual(update["$unset"], tt.wantUnset) {
+				t.Errorf("$unset = %v, want %v", update["$unset"], tt.wantUnset)
+			}
+			if _, ok := set["audioData"]; ok && len(tt.wantUnrestored) > 0 {
+				t.Error("media that can't be restored must not be overwritten")
+			}
+			if !reflect.DeepEqual(unrestored, tt.wantUnrestored) {
+				t.Errorf("unrestored = %v, want %v", unrestored, tt.wantUnrestored)
+			}
+		})
+	}
+}
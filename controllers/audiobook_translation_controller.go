@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetAudiobookTranslation - admin endpoint to add or replace the translation for one locale
+func (ac *AudiobookController) SetAudiobookTranslation(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	locale, err := canonicalLocale(c.Param("locale"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req request.TranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	translations, _ := translationsFromRequest(map[string]request.TranslationRequest{locale: req})
+
+	ok := ac.editTranslations(c, objID, bson.M{"$set": bson.M{
+		"translations." + locale: translations[locale],
+		"updatedAt":              time.Now(),
+	}}, "Failed to save translation")
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translation saved", "locale": locale})
+}
+
+// DeleteAudiobookTranslation - admin endpoint to remove the translation for one locale
+func (ac *AudiobookController) DeleteAudiobookTranslation(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	locale, err := canonicalLocale(c.Param("locale"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ok := ac.editTranslations(c, objID, bson.M{
+		"$unset": bson.M{"translations." + locale: ""},
+		"$set":   bson.M{"updatedAt": time.Now()},
+	}, "Failed to delete translation")
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted"})
+}
+
+// editTranslations applies a translation change to an audiobook outside the trash and records it
+// as a revision, writing the error response itself when it fails
+func (ac *AudiobookController) editTranslations(c *gin.Context, objID primitive.ObjectID, update bson.M, failure string) bool {
+	if err := ac.ensureBaselineRevision(objID); err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record revision"})
+		return false
+	}
+
+	var updated models.Audiobook
+	err := ac.AudiobookCol.FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": objID, "deletedAt": nil},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure})
+		return false
+	}
+
+	adminID, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err := ac.recordRevision(objID, metadataOf(updated), &adminID, 0); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Translation changed but failed to record revision"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	models "live_stream/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetTrashedAudiobooks - admin endpoint to list audiobooks in the trash with their purge date
+func (ac *AudiobookController) GetTrashedAudiobooks(c *gin.Context) {
+	cursor, err := ac.AudiobookCol.Find(context.TODO(), bson.M{"deletedAt": bson.M{"$ne": nil}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	var audiobooks []models.Audiobook
+	cursor.All(context.TODO(), &audiobooks)
+
+	items := make([]gin.H, 0, len(audiobooks))
+	for _, a := range audiobooks {
+		items = append(items, gin.H{
+			"audiobook": a,
+			"purgeAt":   a.DeletedAt.Add(ac.TrashRetention),
+		})
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// RestoreAudiobook - admin endpoint to bring an audiobook back from the trash
+func (ac *AudiobookController) RestoreAudiobook(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	result, err := ac.AudiobookCol.UpdateOne(
+		context.TODO(),
+		bson.M{"_id": objID, "deletedAt": bson.M{"$ne": nil}},
+		bson.M{
+			"$unset": bson.M{"deletedAt": "", "deletedBy": ""},
+			"$set":   bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore audiobook"})
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found in trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Audiobook restored"})
+}
+
+// PurgeAudiobook - admin endpoint to permanently delete a trashed audiobook without waiting for retention
+func (ac *AudiobookController) PurgeAudiobook(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	var audiobook models.Audiobook
+	err = ac.AudiobookCol.FindOne(context.TODO(), bson.M{"_id": objID, "deletedAt": bson.M{"$ne": nil}}).Decode(&audiobook)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found in trash"})
+		return
+	}
+
+	if err := ac.purgeAudiobook(audiobook); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge audiobook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Audiobook permanently deleted"})
+}
+
+// purgeAudiobook permanently removes an audiobook together with everything that references it
+func (ac *AudiobookController) purgeAudiobook(audiobook models.Audiobook) error {
+	ctx := context.TODO()
+	ref := bson.M{"audiobookId": audiobook.ID}
+
+	if _, err := ac.CommentCol.DeleteMany(ctx, ref); err != nil {
+		return err
+	}
+	if _, err := ac.InteractionCol.DeleteMany(ctx, ref); err != nil {
+		return err
+	}
+	if _, err := ac.ReactionCol.DeleteMany(ctx, ref); err != nil {
+		return err
+	}
+	reviewIDs, err := ac.ReviewCol.Distinct(ctx, "_id", ref)
+	if err != nil {
+		return err
+	}
+	if _, err := ac.ReviewVoteCol.DeleteMany(ctx, bson.M{"reviewId": bson.M{"$in": reviewIDs}}); err != nil {
+		return err
+	}
+	if _, err := ac.ReviewCol.DeleteMany(ctx, ref); err != nil {
+		return err
+	}
+	if _, err := ac.RevisionCol.DeleteMany(ctx, ref); err != nil {
+		return err
+	}
+	if _, err := ac.SlugCol.DeleteMany(ctx, bson.M{"entityType": models.SlugEntityAudiobook, "entityId": audiobook.ID}); err != nil {
+		return err
+	}
+
+	// Listener data
+	for _, col := range []*mongo.Collection{ac.ProgressCol, ac.BookmarkCol, ac.ShelfItemCol, ac.SessionCol} {
+		if _, err := col.DeleteMany(ctx, ref); err != nil {
+			return err
+		}
+	}
+
+	// Lists that mention the audiobook keep their other entries
+	if _, err := ac.RelatedCol.DeleteOne(ctx, bson.M{"_id": audiobook.ID}); err != nil {
+		return err
+	}
+	if _, err := ac.RelatedCol.UpdateMany(ctx, bson.M{"related.audiobookId": audiobook.ID}, bson.M{"$pull": bson.M{"related": ref}}); err != nil {
+		return err
+	}
+	if _, err := ac.CollectionCol.UpdateMany(ctx, bson.M{"audiobookIds": audiobook.ID}, bson.M{"$pull": bson.M{"audiobookIds": audiobook.ID}}); err != nil {
+		return err
+	}
+	for _, col := range []*mongo.Collection{ac.PlaylistCol, ac.QueueCol} {
+		_, err := col.UpdateMany(ctx,
+			bson.M{"items.audiobookId": audiobook.ID},
+			bson.M{"$pull": bson.M{"items": ref}, "$inc": bson.M{"version": 1}, "$set": bson.M{"updatedAt": time.Now()}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := ac.forgetAudiobookCounters(ctx, audiobook.ID); err != nil {
+		return err
+	}
+
+	removeMediaFile(audiobook.AudioData)
+	removeMediaFile(audiobook.Thumbnail)
+
+	_, err = ac.AudiobookCol.DeleteOne(ctx, bson.M{"_id": audiobook.ID})
+	return err
+}
+
+// forgetAudiobookCounters removes an audiobook from the Redis engagement buckets, trending
+// rankings and view counts waiting to be flushed, so none of them point at it once it is purged
+func (ac *AudiobookController) forgetAudiobookCounters(ctx context.Context, audiobookID primitive.ObjectID) error {
+	if ac.Redis == nil {
+		return nil
+	}
+	id := audiobookID.Hex()
+	now := time.Now().UTC()
+
+	pipe := ac.Redis.Pipeline()
+	for age := time.Duration(0); age <= engagementRetention; age += time.Hour {
+		pipe.HDel(ctx, engagementBucketPrefix+now.Add(-age).Format(engagementBucketLayout), id)
+	}
+	for name := range trendingWindows {
+		pipe.ZRem(ctx, trendingKeyPrefix+name, id)
+	}
+	pipe.HDel(ctx, pendingViewsKey, id)
+	pipe.HDel(ctx, flushingViewsKey, id)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// removeMediaFile deletes a media file stored on disk under MEDIA_DIR.
+// Inline (base64) media and external URLs are left alone.
+func removeMediaFile(path string) {
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" || path == "" {
+		return
+	}
+
+	full := filepath.Join(mediaDir, filepath.Clean("/"+path))
+	if !strings.HasPrefix(full, filepath.Clean(mediaDir)+string(os.PathSeparator)) {
+		return
+	}
+	if err := os.Remove(full); err != nil && !os.IsNotExist(err) {
+		log.Println("Failed to remove media file:", err)
+	}
+}
+
+// RunTrashPurger permanently deletes audiobooks that have been in the trash longer than TrashRetention.
+// It blocks, so start it in its own goroutine.
+func (ac *AudiobookController) RunTrashPurger(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ac.purgeExpiredTrash(time.Now())
+		<-ticker.C
+	}
+}
+
+func (ac *AudiobookController) purgeExpiredTrash(now time.Time) {
+	cursor, err := ac.AudiobookCol.Find(context.TODO(), bson.M{
+		"deletedAt": bson.M{"$lte": now.Add(-ac.TrashRetention)},
+	})
+	if err != nil {
+		log.Println("Trash purger: failed to fetch expired audiobooks:", err)
+		return
+	}
+
+	var expired []models.Audiobook
+	cursor.All(context.TODO(), &expired)
+
+	for _, audiobook := range expired {
+		if err := ac.purgeAudiobook(audiobook); err != nil {
+			log.Printf("Trash purger: failed to purge audiobook %s: %v", audiobook.ID.Hex(), err)
+		}
+	}
+	if len(expired) > 0 {
+		log.Printf("Trash purger: purged %d audiobook(s)", len(expired))
+	}
+}
@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	models "live_stream/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	engagementBucketPrefix = "engagement:" // Hash per hour: audiobook ID -> weighted engagement
+	engagementBucketLayout = "2006010215"
+	engagementRetention    = 31 * 24 * time.Hour
+	trendingKeyPrefix      = "trending:" // Sorted set per window: audiobook ID -> decayed score
+
+	maxTrendingAudiobooks = 50
+)
+
+// Weight of each kind of engagement towards the trending score
+var engagementWeights = map[string]int64{
+	"view":    1,
+	"like":    3,
+	"comment": 2,
+}
+
+// trendingWindow is how far back a ranking looks and how fast older engagement fades
+type trendingWindow struct {
+	Span     time.Duration
+	HalfLife time.Duration
+}
+
+var trendingWindows = map[string]trendingWindow{
+	"daily":   {Span: 24 * time.Hour, HalfLife: 6 * time.Hour},
+	"weekly":  {Span: 7 * 24 * time.Hour, HalfLife: 2 * 24 * time.Hour},
+	"monthly": {Span: 30 * 24 * time.Hour, HalfLife: 7 * 24 * time.Hour},
+}
+
+// recordEngagement adds one engagement event to the current hourly bucket.
+// Failures are only logged: trending is best effort and must never break the request.
+func recordEngagement(rdb *redis.Client, audiobookID primitive.ObjectID, kind string) {
+	if rdb == nil {
+		return
+	}
+	ctx := context.TODO()
+	key := engagementBucketPrefix + time.Now().UTC().Format(engagementBucketLayout)
+
+	pipe := rdb.Pipeline()
+	pipe.HIncrBy(ctx, key, audiobookID.Hex(), engagementWeights[kind])
+	pipe.Expire(ctx, key, engagementRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Println("Failed to record engagement:", err)
+	}
+}
+
+// GetTrendingAudiobooks - public endpoint listing the currently trending audiobooks.
+// ?window= is daily, weekly (default) or monthly.
+func (ac *AudiobookController) GetTrendingAudiobooks(c *gin.Context) {
+	window := c.DefaultQuery("window", "weekly")
+	if _, ok := trendingWindows[window]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Window must be daily, weekly or monthly"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > maxTrendingAudiobooks {
+		limit = 20
+	}
+
+	filter := loadContentPreferences(c, ac.UserCol).apply(publishedFilter(time.Now()))
+	audiobooks := ac.trendingAudiobooks(context.TODO(), window, filter, limit)
+
+	prefs := preferredLanguages(c)
+	for i := range audiobooks {
+		localizeAudiobook(&audiobooks[i], prefs)
+	}
+	c.Header("Vary", "Accept-Language")
+	c.JSON(http.StatusOK, gin.H{"window": window, "audiobooks": audiobooks})
+}
+
+// trendingAudiobooks returns the top audiobooks of a trending window that match filter, in rank order.
+// When no ranking has been computed yet, it falls back to all-time popularity.
+func (ac *AudiobookController) trendingAudiobooks(ctx context.Context, window string, filter bson.M, limit int) []models.Audiobook {
+	audiobooks := []models.Audiobook{}
+	projection := options.Find().SetProjection(bson.M{"audioData": 0})
+
+	var ranked []string
+	if ac.Redis != nil {
+		// Over-fetch, since some titles may be filtered out for this viewer
+		ranked, _ = ac.Redis.ZRevRange(ctx, trendingKeyPrefix+window, 0, int64(limit*3)).Result()
+	}
+
+	if len(ranked) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(ranked))
+		for _, hex := range ranked {
+			if id, err := primitive.ObjectIDFromHex(hex); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		rankedFilter := copyFilter(filter)
+		if existing, ok := filter["_id"].(bson.M); ok {
+			rankedFilter["_id"] = bson.M{"$in": ids, "$nin": existing["$nin"]}
+		} else {
+			rankedFilter["_id"] = bson.M{"$in": ids}
+		}
+
+		cursor, err := ac.AudiobookCol.Find(ctx, rankedFilter, projection)
+		if err == nil {
+			var found []models.Audiobook
+			cursor.All(ctx, &found)
+			byID := make(map[primitive.ObjectID]models.Audiobook, len(found))
+			for _, a := range found {
+				byID[a.ID] = a
+			}
+			for _, id := range ids {
+				if a, ok := byID[id]; ok && len(audiobooks) < limit {
+					audiobooks = append(audiobooks, a)
+				}
+			}
+			if len(audiobooks) > 0 {
+				return audiobooks
+			}
+		}
+	}
+
+	cursor, err := ac.AudiobookCol.Find(ctx, filter,
+		projection.
+			SetSort(bson.D{{Key: "viewCount", Value: -1}, {Key: "likes", Value: -1}}).
+			SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return audiobooks
+	}
+	cursor.All(ctx, &audiobooks)
+	return audiobooks
+}
+
+// RunTrendingJob periodically recomputes the trending rankings from the hourly engagement buckets.
+// It blocks, so start it in its own goroutine.
+func (ac *AudiobookController) RunTrendingJob(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for name, window := range trendingWindows {
+			if err := ac.computeTrending(name, window); err != nil {
+				log.Println("Trending job failed for", name, "window:", err)
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// computeTrending sums each hourly bucket inside the window, decayed exponentially by its age,
+// and atomically replaces the window's sorted set.
+func (ac *AudiobookController) computeTrending(name string, window trendingWindow) error {
+	if ac.Redis == nil {
+		return nil
+	}
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	scores := map[string]float64{}
+	for age := time.Duration(0); age < window.Span; age += time.Hour {
+		bucket, err := ac.Redis.HGetAll(ctx, engagementBucketPrefix+now.Add(-age).Format(engagementBucketLayout)).Result()
+		if err != nil {
+			return err
+		}
+		decay := math.Pow(0.5, age.Hours()/window.HalfLife.Hours())
+		for id, count := range bucket {
+			n, err := strconv.ParseFloat(count, 64)
+			if err != nil {
+				continue
+			}
+			scores[id] += n * decay
+		}
+	}
+
+	key := trendingKeyPrefix + name
+	if len(scores) == 0 {
+		return ac.Redis.Del(ctx, key).Err()
+	}
+
+	members := make([]redis.Z, 0, len(scores))
+	for id, score := range scores {
+		members = append(members, redis.Z{Score: score, Member: id})
+	}
+
+	tmp := key + ":tmp"
+	pipe := ac.Redis.TxPipeline()
+	pipe.Del(ctx, tmp)
+	pipe.ZAdd(ctx, tmp, members...)
+	pipe.Rename(ctx, tmp, key)
+	_, err := pipe.Exec(ctx)
+	return err
+}
@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// publishedFilter matches audiobooks that are visible to the public at the given time.
+// Documents created before the publishing workflow have no status and fall back to displayOnSite.
+// Audiobooks in the trash are never visible.
+func publishedFilter(now time.Time) bson.M {
+	return bson.M{
+		"deletedAt": nil,
+		"$or": bson.A{
+			bson.M{
+				"status": models.AudiobookStatusPublished,
+				"$or": bson.A{
+					bson.M{"unpublishAt": nil},
+					bson.M{"unpublishAt": bson.M{"$gt": now}},
+				},
+			},
+			bson.M{"status": bson.M{"$exists": false}, "displayOnSite": true},
+		},
+	}
+}
+
+// GetAllAudiobooks - admin endpoint to list audiobooks in any state, optionally filtered by ?status=.
+// Trashed audiobooks are listed separately by GetTrashedAudiobooks.
+func (ac *AudiobookController) GetAllAudiobooks(c *gin.Context) {
+	filter := bson.M{"deletedAt": nil}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+
+	cursor, err := ac.AudiobookCol.Find(context.TODO(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audiobooks"})
+		return
+	}
+	var audiobooks []models.Audiobook
+	cursor.All(context.TODO(), &audiobooks)
+	c.JSON(http.StatusOK, audiobooks)
+}
+
+// SubmitAudiobookForReview - admin endpoint to move a draft into review
+func (ac *AudiobookController) SubmitAudiobookForReview(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	submitterID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ac.transitionAudiobook(c, objID,
+		[]string{models.AudiobookStatusDraft, models.AudiobookStatusUnpublished},
+		bson.M{"status": models.AudiobookStatusInReview, "submittedBy": submitterID},
+		"Audiobook submitted for review",
+	)
+}
+
+// ApproveAudiobook - admin endpoint to approve an audiobook in review.
+// It is published immediately unless a future publishAt is set, in which case it is scheduled.
+// The admin who submitted the audiobook can't approve it.
+func (ac *AudiobookController) ApproveAudiobook(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	var req request.ReviewAudiobookRequest
+	c.ShouldBindJSON(&req)
+
+	reviewerID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var audiobook models.Audiobook
+	err = ac.AudiobookCol.FindOne(context.TODO(), bson.M{"_id": objID, "deletedAt": nil}).Decode(&audiobook)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return
+	}
+	if audiobook.SubmittedBy != nil && *audiobook.SubmittedBy == reviewerID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Another admin has to approve your submission"})
+		return
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"reviewedBy": reviewerID,
+		"reviewedAt": now,
+		"reviewNote": req.Note,
+	}
+	message := "Audiobook approved and published"
+	if audiobook.PublishAt != nil && audiobook.PublishAt.After(now) {
+		update["status"] = models.AudiobookStatusScheduled
+		update["displayOnSite"] = false
+		message = "Audiobook approved and scheduled"
+	} else {
+		update["status"] = models.AudiobookStatusPublished
+		update["displayOnSite"] = true
+		update["publishedAt"] = now
+	}
+
+	ac.transitionAudiobook(c, objID, []string{models.AudiobookStatusInReview}, update, message)
+}
+
+// RejectAudiobook - admin endpoint to send an audiobook in review back to draft
+func (ac *AudiobookController) RejectAudiobook(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	var req request.ReviewAudiobookRequest
+	c.ShouldBindJSON(&req)
+
+	reviewerID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ac.transitionAudiobook(c, objID,
+		[]string{models.AudiobookStatusInReview},
+		bson.M{
+			"status":     models.AudiobookStatusDraft,
+			"reviewedBy": reviewerID,
+			"reviewedAt": time.Now(),
+			"reviewNote": req.Note,
+		},
+		"Audiobook rejected",
+	)
+}
+
+// UnpublishAudiobook - admin endpoint to take a published or scheduled audiobook off the site
+func (ac *AudiobookController) UnpublishAudiobook(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	ac.transitionAudiobook(c, objID,
+		[]string{models.AudiobookStatusPublished, models.AudiobookStatusScheduled},
+		bson.M{"status": models.AudiobookStatusUnpublished, "displayOnSite": false},
+		"Audiobook unpublished",
+	)
+}
+
+// ScheduleAudiobook - admin endpoint to set the publish/unpublish window.
+// Omitted dates clear the corresponding side of the window, except that a scheduled audiobook
+// keeps needing a publish date: unpublish it first to drop the schedule.
+func (ac *AudiobookController) ScheduleAudiobook(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	var req request.ScheduleAudiobookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.PublishAt != nil && req.UnpublishAt != nil && !req.UnpublishAt.After(*req.PublishAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unpublishAt must be after publishAt"})
+		return
+	}
+
+	set := bson.M{"updatedAt": time.Now()}
+	unset := bson.M{}
+	if req.PublishAt != nil {
+		set["publishAt"] = *req.PublishAt
+	} else {
+		unset["publishAt"] = ""
+	}
+	if req.UnpublishAt != nil {
+		set["unpublishAt"] = *req.UnpublishAt
+	} else {
+		unset["unpublishAt"] = ""
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	filter := bson.M{"_id": objID, "deletedAt": nil}
+	if req.PublishAt == nil {
+		// Without publishAt the scheduler would never publish it
+		filter["status"] = bson.M{"$ne": models.AudiobookStatusScheduled}
+	}
+
+	result, err := ac.AudiobookCol.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule audiobook"})
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		count, _ := ac.AudiobookCol.CountDocuments(context.TODO(), bson.M{"_id": objID, "deletedAt": nil})
+		if count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "A scheduled audiobook needs publishAt, unpublish it to clear the schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Audiobook schedule updated"})
+}
+
+// transitionAudiobook applies update only if the audiobook is currently in one of the from states.
+// Trashed audiobooks have to be restored first.
+func (ac *AudiobookController) transitionAudiobook(c *gin.Context, objID primitive.ObjectID, from []string, update bson.M, message string) {
+	update["updatedAt"] = time.Now()
+
+	result, err := ac.AudiobookCol.UpdateOne(
+		context.TODO(),
+		bson.M{"_id": objID, "deletedAt": nil, "status": bson.M{"$in": from}},
+		bson.M{"$set": update},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update audiobook status"})
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		count, _ := ac.AudiobookCol.CountDocuments(context.TODO(), bson.M{"_id": objID, "deletedAt": nil})
+		if count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "Audiobook is not in a valid state for this action"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message, "status": update["status"]})
+}
+
+// BackfillAudiobookStatus gives audiobooks created before the publishing workflow a status
+// from their displayOnSite flag, so the workflow transitions apply to them
+func (ac *AudiobookController) BackfillAudiobookStatus() {
+	ctx := context.TODO()
+	backfills := []struct {
+		visible bson.M
+		status  string
+	}{
+		{bson.M{"$eq": true}, models.AudiobookStatusPublished},
+		{bson.M{"$ne": true}, models.AudiobookStatusDraft},
+	}
+	for _, b := range backfills {
+		_, err := ac.AudiobookCol.UpdateMany(ctx,
+			bson.M{"status": bson.M{"$exists": false}, "displayOnSite": b.visible},
+			bson.M{"$set": bson.M{"status": b.status}},
+		)
+		if err != nil {
+			log.Println("Status backfill: failed to update audiobooks:", err)
+		}
+	}
+}
+
+// RunPublishScheduler publishes and unpublishes audiobooks whose schedule is due.
+// It blocks, so start it in its own goroutine.
+func (ac *AudiobookController) RunPublishScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ac.applyPublishSchedule(time.Now())
+		<-ticker.C
+	}
+}
+
+func (ac *AudiobookController) applyPublishSchedule(now time.Time) {
+	ctx := context.TODO()
+
+	published, err := ac.AudiobookCol.UpdateMany(ctx,
+		bson.M{
+			"status":    models.AudiobookStatusScheduled,
+			"publishAt": bson.M{"$lte": now},
+		},
+		bson.M{"$set": bson.M{
+			"status":        models.AudiobookStatusPublished,
+			"displayOnSite": true,
+			"publishedAt":   now,
+			"updatedAt":     now,
+		}},
+	)
+	if err != nil {
+		log.Println("Publish scheduler: failed to publish audiobooks:", err)
+	} else if published.ModifiedCount > 0 {
+		log.Printf("Publish scheduler: published %d audiobook(s)", published.ModifiedCount)
+	}
+
+	unpublished, err := ac.AudiobookCol.UpdateMany(ctx,
+		bson.M{
+			"status":      models.AudiobookStatusPublished,
+			"unpublishAt": bson.M{"$lte": now},
+		},
+		bson.M{"$set": bson.M{
+			"status":        models.AudiobookStatusUnpublished,
+			"displayOnSite": false,
+			"updatedAt":     now,
+		}},
+	)
+	if err != nil {
+		log.Println("Publish scheduler: failed to unpublish audiobooks:", err)
+	} else if unpublished.ModifiedCount > 0 {
+		log.Printf("Publish scheduler: unpublished %d audiobook(s)", unpublished.ModifiedCount)
+	}
+}
@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxBookmarkNoteLength = 2000
+	maxClipSeconds        = 10 * 60
+)
+
+// GetBookmarks - authenticated endpoint listing the user's bookmarks and clips.
+// Optional ?audiobookId= and ?type= (bookmark or clip) filters.
+func (uc *UserController) GetBookmarks(c *gin.Context) {
+	filter, ok := bookmarkFilter(c)
+	if !ok {
+		return
+	}
+
+	bookmarks, err := uc.findBookmarks(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookmarks"})
+		return
+	}
+	c.JSON(http.StatusOK, bookmarks)
+}
+
+// CreateBookmark - authenticated endpoint to save a bookmark or clip in an audiobook
+func (uc *UserController) CreateBookmark(c *gin.Context) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req request.CreateBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Type == "" {
+		req.Type = models.BookmarkTypeBookmark
+	}
+	if req.Type == models.BookmarkTypeBookmark {
+		req.EndSeconds = 0
+	}
+
+	now := time.Now()
+	bookmark := models.Bookmark{
+		UserID:          uid,
+		AudiobookID:     req.AudiobookID,
+		Type:            req.Type,
+		PositionSeconds: req.PositionSeconds,
+		EndSeconds:      req.EndSeconds,
+		Chapter:         req.Chapter,
+		Note:            strings.TrimSpace(req.Note),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if msg := validateBookmark(bookmark); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	count, err := uc.StreamCol.CountDocuments(context.TODO(), bson.M{"_id": req.AudiobookID, "deletedAt": nil})
+	if err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return
+	}
+
+	result, err := uc.BookmarkCol.InsertOne(context.TODO(), bookmark)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save bookmark"})
+		return
+	}
+	bookmark.ID = result.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusOK, bookmark)
+}
+
+// UpdateBookmark - authenticated endpoint to move a bookmark/clip or edit its note
+func (uc *UserController) UpdateBookmark(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bookmark ID"})
+		return
+	}
+
+	var req request.UpdateBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.TODO()
+	owned := bson.M{"_id": objID, "userId": uid}
+
+	var bookmark models.Bookmark
+	if err := uc.BookmarkCol.FindOne(ctx, owned).Decode(&bookmark); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found"})
+		return
+	}
+	if req.PositionSeconds != nil {
+		bookmark.PositionSeconds = *req.PositionSeconds
+	}
+	if req.EndSeconds != nil && bookmark.Type == models.BookmarkTypeClip {
+		bookmark.EndSeconds = *req.EndSeconds
+	}
+	if req.Chapter != nil {
+		bookmark.Chapter = *req.Chapter
+	}
+	if req.Note != nil {
+		bookmark.Note = strings.TrimSpace(*req.Note)
+	}
+	if msg := validateBookmark(bookmark); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	bookmark.UpdatedAt = time.Now()
+
+	_, err = uc.BookmarkCol.UpdateOne(ctx, owned, bson.M{"$set": bson.M{
+		"positionSeconds": bookmark.PositionSeconds,
+		"endSeconds":      bookmark.EndSeconds,
+		"chapter":         bookmark.Chapter,
+		"note":            bookmark.Note,
+		"updatedAt":       bookmark.UpdatedAt,
+	}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update bookmark"})
+		return
+	}
+	c.JSON(http.StatusOK, bookmark)
+}
+
+// DeleteBookmark - authenticated endpoint to remove one of the user's bookmarks or clips
+func (uc *UserController) DeleteBookmark(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bookmark ID"})
+		return
+	}
+
+	result, err := uc.BookmarkCol.DeleteOne(context.TODO(), bson.M{"_id": objID, "userId": uid})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete bookmark"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Bookmark deleted"})
+}
+
+// ExportBookmarks - authenticated endpoint to download the user's bookmarks and clips
+// as ?format=markdown or json (default), grouped by audiobook. Accepts the same filters as GetBookmarks.
+func (uc *UserController) ExportBookmarks(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "markdown" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format must be json or markdown"})
+		return
+	}
+
+	filter, ok := bookmarkFilter(c)
+	if !ok {
+		return
+	}
+	bookmarks, err := uc.findBookmarks(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookmarks"})
+		return
+	}
+
+	// Group by audiobook, keeping audiobooks in order of their first bookmark
+	type audiobookBookmarks struct {
+		AudiobookID primitive.ObjectID `json:"audiobookId"`
+		Name        string             `json:"name"`
+		Bookmarks   []models.Bookmark  `json:"bookmarks"`
+	}
+	groups := []*audiobookBookmarks{}
+	byAudiobook := map[primitive.ObjectID]*audiobookBookmarks{}
+	ids := []primitive.ObjectID{}
+	for _, b := range bookmarks {
+		group := byAudiobook[b.AudiobookID]
+		if group == nil {
+			group = &audiobookBookmarks{AudiobookID: b.AudiobookID}
+			byAudiobook[b.AudiobookID] = group
+			groups = append(groups, group)
+			ids = append(ids, b.AudiobookID)
+		}
+		group.Bookmarks = append(group.Bookmarks, b)
+	}
+
+	if len(ids) > 0 {
+		cursor, err := uc.StreamCol.Find(context.TODO(), bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"name": 1}))
+		if err == nil {
+			var audiobooks []models.Audiobook
+			cursor.All(context.TODO(), &audiobooks)
+			for _, a := range audiobooks {
+				byAudiobook[a.ID].Name = a.Name
+			}
+		}
+	}
+
+	if format == "json" {
+		c.Header("Content-Disposition", "attachment; filename=bookmarks.json")
+		c.JSON(http.StatusOK, groups)
+		return
+	}
+
+	var md strings.Builder
+	md.WriteString("# My bookmarks\n")
+	for _, group := range groups {
+		name := group.Name
+		if name == "" {
+			name = "Removed audiobook"
+		}
+		md.WriteString("\n## " + name + "\n\n")
+		for _, b := range group.Bookmarks {
+			if b.Type == models.BookmarkTypeClip {
+				md.WriteString("- **[" + formatTimestamp(b.PositionSeconds) + " – " + formatTimestamp(b.EndSeconds) + "]** (clip)")
+			} else {
+				md.WriteString("- **[" + formatTimestamp(b.PositionSeconds) + "]**")
+			}
+			if b.Chapter > 0 {
+				md.WriteString(fmt.Sprintf(" Chapter %d", b.Chapter))
+			}
+			if b.Note != "" {
+				md.WriteString(" " + strings.ReplaceAll(b.Note, "\n", "\n  "))
+			}
+			md.WriteString("\n")
+		}
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=bookmarks.md")
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(md.String()))
+}
+
+// bookmarkFilter builds the query for the current user's bookmarks from ?audiobookId= and ?type=
+func bookmarkFilter(c *gin.Context) (bson.M, bool) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+
+	filter := bson.M{"userId": uid}
+	if id := c.Query("audiobookId"); id != "" {
+		audiobookID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+			return nil, false
+		}
+		filter["audiobookId"] = audiobookID
+	}
+	if t := c.Query("type"); t != "" {
+		filter["type"] = t
+	}
+	return filter, true
+}
+
+// findBookmarks returns matching bookmarks ordered by audiobook and position
+func (uc *UserController) findBookmarks(filter bson.M) ([]models.Bookmark, error) {
+	cursor, err := uc.BookmarkCol.Find(context.TODO(), filter,
+		options.Find().SetSort(bson.D{{Key: "audiobookId", Value: 1}, {Key: "positionSeconds", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	bookmarks := []models.Bookmark{}
+	err = cursor.All(context.TODO(), &bookmarks)
+	return bookmarks, err
+}
+
+// validateBookmark returns an error message for an invalid bookmark, or "" if it is valid
+func validateBookmark(b models.Bookmark) string {
+	switch {
+	case b.Type != models.BookmarkTypeBookmark && b.Type != models.BookmarkTypeClip:
+		return "Type must be bookmark or clip"
+	case b.PositionSeconds < 0 || b.Chapter < 0:
+		return "Invalid position"
+	case b.Type == models.BookmarkTypeClip && b.EndSeconds <= b.PositionSeconds:
+		return "Clip must end after it starts"
+	case b.Type == models.BookmarkTypeClip && b.EndSeconds-b.PositionSeconds > maxClipSeconds:
+		return "Clips can be at most 10 minutes long"
+	case len(b.Note) > maxBookmarkNoteLength:
+		return "Note is too long"
+	}
+	return ""
+}
+
+// formatTimestamp renders seconds as h:mm:ss (or m:ss under an hour)
+func formatTimestamp(seconds float64) string {
+	s := int(seconds)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
@@ -0,0 +1,438 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/text/language"
+)
+
+const defaultSmartCollectionLimit = 20
+
+type CollectionController struct {
+	CollectionCol *mongo.Collection
+	AudiobookCol  *mongo.Collection
+	SlugCol       *mongo.Collection
+	UserCol       *mongo.Collection
+}
+
+// collectionShelf is a collection together with the audiobooks it currently shows
+type collectionShelf struct {
+	models.Collection
+	Audiobooks []models.Audiobook `json:"audiobooks"`
+}
+
+// activeCollectionFilter matches collections that are switched on and inside their schedule
+func activeCollectionFilter(now time.Time) bson.M {
+	return bson.M{
+		"isActive": true,
+		"$and": bson.A{
+			bson.M{"$or": bson.A{bson.M{"startsAt": nil}, bson.M{"startsAt": bson.M{"$lte": now}}}},
+			bson.M{"$or": bson.A{bson.M{"endsAt": nil}, bson.M{"endsAt": bson.M{"$gt": now}}}},
+		},
+	}
+}
+
+// validateCollectionWindow checks that a collection's schedule ends after it starts; either end may be open
+func validateCollectionWindow(startsAt, endsAt *time.Time) error {
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return fmt.Errorf("endsAt must be after startsAt")
+	}
+	return nil
+}
+
+func smartRuleFromRequest(req *request.SmartCollectionRuleRequest) (*models.SmartCollectionRule, error) {
+	rule := &models.SmartCollectionRule{
+		PublishedWithinDays: req.PublishedWithinDays,
+		SortBy:              req.SortBy,
+		Limit:               req.Limit,
+	}
+	if tags := normalizeTags([]string{req.Genre}); len(tags) > 0 {
+		rule.Genre = tags[0]
+	}
+	if req.Language != "" {
+		locale, err := canonicalLocale(req.Language)
+		if err != nil {
+			return nil, err
+		}
+		rule.Language = locale
+	}
+	switch rule.SortBy {
+	case "":
+		rule.SortBy = "newest"
+	case "newest", "popular", "liked":
+	default:
+		return nil, fmt.Errorf("sortBy must be newest, popular or liked")
+	}
+	return rule, nil
+}
+
+// CreateCollection - admin endpoint to create a curated or smart collection
+func (cc *CollectionController) CreateCollection(c *gin.Context) {
+	var req request.CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	collection := models.Collection{
+		Title:       req.Title,
+		Description: req.Description,
+		Artwork:     req.Artwork,
+		Type:        req.Type,
+		Position:    req.Position,
+		IsActive:    req.IsActive,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	switch req.Type {
+	case models.CollectionTypeCurated:
+		collection.AudiobookIDs = req.AudiobookIDs
+	case models.CollectionTypeSmart:
+		if req.Rule == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Smart collections require a rule"})
+			return
+		}
+		rule, err := smartRuleFromRequest(req.Rule)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		collection.Rule = rule
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be curated or smart"})
+		return
+	}
+
+	if err := validateCollectionWindow(req.StartsAt, req.EndsAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cc.CollectionCol.InsertOne(context.TODO(), collection)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create collection"})
+		return
+	}
+
+	collectionID := result.InsertedID.(primitive.ObjectID)
+	cc.updateCollectionSlug(collectionID, collection.Title)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Collection created", "id": collectionID})
+}
+
+// UpdateCollection - admin endpoint to update a collection
+func (cc *CollectionController) UpdateCollection(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	var req request.UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := bson.M{}
+	if req.Title != "" {
+		update["title"] = req.Title
+	}
+	if req.Description != nil {
+		update["description"] = *req.Description
+	}
+	if req.Artwork != nil {
+		update["artwork"] = *req.Artwork
+	}
+	if req.AudiobookIDs != nil {
+		update["audiobookIds"] = req.AudiobookIDs
+	}
+	if req.Rule != nil {
+		rule, err := smartRuleFromRequest(req.Rule)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		update["rule"] = rule
+	}
+	if req.Position != nil {
+		update["position"] = *req.Position
+	}
+	if req.IsActive != nil {
+		update["isActive"] = *req.IsActive
+	}
+
+	filter := bson.M{"_id": objID}
+	changes := bson.M{}
+	if req.StartsAt.Set || req.EndsAt.Set {
+		// The new schedule is checked together with the end that isn't changing
+		var current models.Collection
+		if err := cc.CollectionCol.FindOne(context.TODO(), filter).Decode(&current); err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update collection"})
+			return
+		}
+		startsAt, endsAt := current.StartsAt, current.EndsAt
+		if req.StartsAt.Set {
+			startsAt = req.StartsAt.Time
+		}
+		if req.EndsAt.Set {
+			endsAt = req.EndsAt.Time
+		}
+		if err := validateCollectionWindow(startsAt, endsAt); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter["startsAt"], filter["endsAt"] = current.StartsAt, current.EndsAt
+
+		unset := bson.M{}
+		for field, value := range map[string]request.OptionalTime{"startsAt": req.StartsAt, "endsAt": req.EndsAt} {
+			if !value.Set {
+				continue
+			}
+			if value.Time == nil {
+				unset[field] = ""
+			} else {
+				update[field] = *value.Time
+			}
+		}
+		if len(unset) > 0 {
+			changes["$unset"] = unset
+		}
+	}
+	update["updatedAt"] = time.Now()
+	changes["$set"] = update
+
+	var updated models.Collection
+	err = cc.CollectionCol.FindOneAndUpdate(
+		context.TODO(),
+		filter,
+		changes,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments && len(filter) > 1 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Collection schedule was changed concurrently, please retry"})
+		return
+	}
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update collection"})
+		return
+	}
+
+	if req.Title != "" {
+		cc.updateCollectionSlug(objID, updated.Title)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection updated"})
+}
+
+// DeleteCollection - admin endpoint to delete a collection
+func (cc *CollectionController) DeleteCollection(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	result, err := cc.CollectionCol.DeleteOne(context.TODO(), bson.M{"_id": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete collection"})
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return
+	}
+
+	cc.SlugCol.DeleteMany(context.TODO(), bson.M{"entityType": models.SlugEntityCollection, "entityId": objID})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted"})
+}
+
+// GetAllCollections - admin endpoint to list every collection, including inactive ones
+func (cc *CollectionController) GetAllCollections(c *gin.Context) {
+	cursor, err := cc.CollectionCol.Find(context.TODO(), bson.M{}, options.Find().SetSort(bson.M{"position": 1}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collections"})
+		return
+	}
+	collections := []models.Collection{}
+	cursor.All(context.TODO(), &collections)
+	c.JSON(http.StatusOK, collections)
+}
+
+// GetCollection - public endpoint to get an active collection (by ID or slug) with its audiobooks
+func (cc *CollectionController) GetCollection(c *gin.Context) {
+	objID, ok := resolveEntityParam(c, cc.SlugCol, models.SlugEntityCollection, "id")
+	if !ok {
+		return
+	}
+
+	filter := activeCollectionFilter(time.Now())
+	filter["_id"] = objID
+
+	var collection models.Collection
+	if err := cc.CollectionCol.FindOne(context.TODO(), filter).Decode(&collection); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return
+	}
+
+	prefs := loadContentPreferences(c, cc.UserCol)
+	c.Header("Vary", "Accept-Language")
+	c.JSON(http.StatusOK, collectionShelf{
+		Collection: collection,
+		Audiobooks: cc.collectionAudiobooks(collection, prefs, preferredLanguages(c), 0),
+	})
+}
+
+// GetHomeShelves - public endpoint returning the homepage shelf layout: every active collection
+// in position order, each with up to ?limit= (default 10) audiobooks
+func (cc *CollectionController) GetHomeShelves(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	cursor, err := cc.CollectionCol.Find(context.TODO(),
+		activeCollectionFilter(time.Now()),
+		options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "createdAt", Value: 1}}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shelves"})
+		return
+	}
+	var collections []models.Collection
+	cursor.All(context.TODO(), &collections)
+
+	prefs := loadContentPreferences(c, cc.UserCol)
+	langs := preferredLanguages(c)
+
+	shelves := make([]collectionShelf, 0, len(collections))
+	for _, collection := range collections {
+		audiobooks := cc.collectionAudiobooks(collection, prefs, langs, limit)
+		if len(audiobooks) == 0 {
+			continue
+		}
+		shelves = append(shelves, collectionShelf{Collection: collection, Audiobooks: audiobooks})
+	}
+
+	c.Header("Vary", "Accept-Language")
+	c.JSON(http.StatusOK, gin.H{"shelves": shelves})
+}
+
+// collectionAudiobooks resolves the published audiobooks a collection currently shows,
+// respecting the viewer's content settings. A limit of 0 means the collection's own limit.
+func (cc *CollectionController) collectionAudiobooks(collection models.Collection, prefs contentPreferences, langs []language.Tag, limit int) []models.Audiobook {
+	ctx := context.TODO()
+	now := time.Now()
+	filter := prefs.apply(publishedFilter(now))
+	audiobooks := []models.Audiobook{}
+
+	switch collection.Type {
+	case models.CollectionTypeCurated:
+		if len(collection.AudiobookIDs) == 0 {
+			return audiobooks
+		}
+		filter["_id"] = bson.M{"$in": collection.AudiobookIDs}
+
+		cursor, err := cc.AudiobookCol.Find(ctx, filter, options.Find().SetProjection(bson.M{"audioData": 0}))
+		if err != nil {
+			log.Println("Failed to fetch collection audiobooks:", err)
+			return audiobooks
+		}
+		var found []models.Audiobook
+		cursor.All(ctx, &found)
+
+		byID := make(map[primitive.ObjectID]models.Audiobook, len(found))
+		for _, a := range found {
+			byID[a.ID] = a
+		}
+		for _, id := range collection.AudiobookIDs {
+			if a, ok := byID[id]; ok {
+				audiobooks = append(audiobooks, a)
+			}
+			if limit > 0 && len(audiobooks) == limit {
+				break
+			}
+		}
+
+	case models.CollectionTypeSmart:
+		rule := collection.Rule
+		if rule == nil {
+			return audiobooks
+		}
+		if rule.Genre != "" {
+			filter["genres"] = rule.Genre
+		}
+		if rule.Language != "" {
+			filter["language"] = rule.Language
+		}
+		if rule.PublishedWithinDays > 0 {
+			filter["publishedAt"] = bson.M{"$gte": now.AddDate(0, 0, -rule.PublishedWithinDays)}
+		}
+
+		sort := bson.D{{Key: "publishedAt", Value: -1}, {Key: "createdAt", Value: -1}}
+		switch rule.SortBy {
+		case "popular":
+			sort = bson.D{{Key: "viewCount", Value: -1}}
+		case "liked":
+			sort = bson.D{{Key: "likes", Value: -1}}
+		}
+
+		n := rule.Limit
+		if n <= 0 {
+			n = defaultSmartCollectionLimit
+		}
+		if limit > 0 && limit < n {
+			n = limit
+		}
+
+		cursor, err := cc.AudiobookCol.Find(ctx, filter,
+			options.Find().SetSort(sort).SetLimit(int64(n)).SetProjection(bson.M{"audioData": 0}),
+		)
+		if err != nil {
+			log.Println("Failed to fetch smart collection audiobooks:", err)
+			return audiobooks
+		}
+		cursor.All(ctx, &audiobooks)
+	}
+
+	for i := range audiobooks {
+		localizeAudiobook(&audiobooks[i], langs)
+	}
+	return audiobooks
+}
+
+// updateCollectionSlug assigns a slug for the collection's title and stores it on the collection
+func (cc *CollectionController) updateCollectionSlug(collectionID primitive.ObjectID, title string) {
+	slug, err := assignSlug(context.TODO(), cc.SlugCol, models.SlugEntityCollection, collectionID, title)
+	if err != nil {
+		log.Printf("Failed to assign slug for collection %s: %v", collectionID.Hex(), err)
+		return
+	}
+	cc.CollectionCol.UpdateOne(context.TODO(), bson.M{"_id": collectionID}, bson.M{"$set": bson.M{"slug": slug}})
+}
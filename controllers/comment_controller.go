@@ -2,30 +2,116 @@ package controllers
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	models "live_stream/models"
 	request "live_stream/models/requests"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	maxCommentsPerPage = 100
+	maxCommentEdits    = 20               // Versions kept in a comment's edit history
+	commentEditWindow  = 15 * time.Minute // How long authors can edit their comments
+)
+
+// Sort orders for comment lists; _id breaks ties so cursors are stable
+var commentSorts = map[string]bson.D{
+	"newest": {{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}},
+	"oldest": {{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}},
+	"top":    {{Key: "replyCount", Value: -1}, {Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}},
+}
+
+// commentCursor is the position of the last comment of a page, handed to clients as an opaque token
+type commentCursor struct {
+	Timestamp  time.Time          `json:"t"`
+	ID         primitive.ObjectID `json:"id"`
+	ReplyCount int                `json:"r"`
+}
+
+func cursorAfterComment(comment models.Comment) commentCursor {
+	return commentCursor{Timestamp: comment.Timestamp, ID: comment.ID, ReplyCount: comment.ReplyCount}
+}
+
+func (cur commentCursor) encode() string {
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCommentCursor(token string) (commentCursor, error) {
+	var cur commentCursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cur, err
+	}
+	err = json.Unmarshal(raw, &cur)
+	return cur, err
+}
+
+// filter matches the comments that come after the cursor in the given sort order
+func (cur commentCursor) filter(sortName string) bson.M {
+	op := "$lt"
+	if sortName == "oldest" {
+		op = "$gt"
+	}
+	after := bson.A{
+		bson.M{"timestamp": bson.M{op: cur.Timestamp}},
+		bson.M{"timestamp": cur.Timestamp, "_id": bson.M{op: cur.ID}},
+	}
+	if sortName != "top" {
+		return bson.M{"$or": after}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{"replyCount": bson.M{"$lt": cur.ReplyCount}},
+		bson.M{"replyCount": cur.ReplyCount, "$or": after},
+	}}
+}
+
 type CommentController struct {
-	CommentCol *mongo.Collection
-	UserCol    *mongo.Collection
+	CommentCol   *mongo.Collection
+	AudiobookCol *mongo.Collection
+	UserCol      *mongo.Collection
+	SlugCol      *mongo.Collection
+	Redis        *redis.Client
+
+	hub     *commentHub // Local live stream clients, fed by RunCommentHub
+	hubOnce sync.Once
+}
+
+// requirePublishedAudiobook checks that an audiobook is published: drafts, scheduled titles and titles
+// in the trash take no comments and have none to show. Writes the error response itself.
+func (cc *CommentController) requirePublishedAudiobook(c *gin.Context, audiobookID primitive.ObjectID) bool {
+	published := publishedFilter(time.Now())
+	published["_id"] = audiobookID
+	if count, err := cc.AudiobookCol.CountDocuments(context.TODO(), published); err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return false
+	}
+	return true
 }
 
-// AddComment - authenticated users
+// AddComment - authenticated users. With parentId the comment is a reply in that comment's thread.
 func (cc *CommentController) AddComment(c *gin.Context) {
 	var req request.AddCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !cc.requirePublishedAudiobook(c, req.AudiobookID) {
+		return
+	}
 
 	userID := c.GetString("user_id")
 	uid, _ := primitive.ObjectIDFromHex(userID)
@@ -43,50 +129,320 @@ func (cc *CommentController) AddComment(c *gin.Context) {
 		UserID:      uid,
 		UserName:    user.FullName,
 		Message:     req.Message,
-		IsAdmin:     req.IsAdmin,
+		IsAdmin:     user.IsAdmin,
 		Timestamp:   now,
 		UpdatedAt:   now,
 		IsDeleted:   false,
 	}
 
-	cc.CommentCol.InsertOne(context.TODO(), comment)
-	c.JSON(http.StatusOK, gin.H{"message": "Comment added"})
+	if req.Position != nil || req.Chapter != nil {
+		if !cc.validAnchor(req.AudiobookID, req.Position, req.Chapter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playback position"})
+			return
+		}
+		comment.Position, comment.Chapter = req.Position, req.Chapter
+	}
+
+	if req.ParentID != nil {
+		var parent models.Comment
+		err := cc.CommentCol.FindOne(context.TODO(), bson.M{"_id": *req.ParentID, "audiobookId": req.AudiobookID, "isDeleted": false}).Decode(&parent)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Parent comment not found"})
+			return
+		}
+		// Past the depth limit, the reply joins the parent's own thread instead of nesting further
+		if parent.Depth >= models.MaxCommentDepth {
+			comment.ParentID = parent.ParentID
+			comment.Depth = parent.Depth
+		} else {
+			comment.ParentID = &parent.ID
+			comment.Depth = parent.Depth + 1
+		}
+		// Replies belong to the same moment as the comment they answer
+		comment.Position, comment.Chapter = parent.Position, parent.Chapter
+	}
+
+	result, err := cc.CommentCol.InsertOne(context.TODO(), comment)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add comment"})
+		return
+	}
+	comment.ID = result.InsertedID.(primitive.ObjectID)
+	if comment.ParentID != nil {
+		// The parent may have been deleted since it was looked up: take the reply back then
+		inc, err := cc.CommentCol.UpdateOne(context.TODO(),
+			bson.M{"_id": *comment.ParentID, "isDeleted": false},
+			bson.M{"$inc": bson.M{"replyCount": 1}},
+		)
+		if err != nil || inc.MatchedCount == 0 {
+			if _, delErr := cc.CommentCol.DeleteOne(context.TODO(), bson.M{"_id": comment.ID}); delErr != nil {
+				log.Println("Failed to remove orphaned reply:", delErr)
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add comment"})
+			} else {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Parent comment not found"})
+			}
+			return
+		}
+	}
+	recordEngagement(cc.Redis, comment.AudiobookID, "comment")
+	publishCommentEvent(cc.Redis, commentCreated, comment)
+	c.JSON(http.StatusOK, gin.H{"message": "Comment added", "id": result.InsertedID})
 }
 
-// GetComments - public
+// GetComments - public, audiobook addressed by ID or slug.
+// Lists top-level comments, or the direct replies to ?parentId=. ?sort= is newest (default), oldest
+// or top (most replies); pages are fetched with ?limit= and the nextCursor of the previous page as ?cursor=.
 func (cc *CommentController) GetComments(c *gin.Context) {
-	audiobookID := c.Param("id") // Changed from streamID
-	objID, _ := primitive.ObjectIDFromHex(audiobookID)
+	objID, ok := resolveAudiobookParam(c, cc.SlugCol)
+	if !ok {
+		return
+	}
+
+	sortName := c.DefaultQuery("sort", "newest")
+	sort, ok := commentSorts[sortName]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sort must be newest, oldest or top"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > maxCommentsPerPage {
+		limit = 20
+	}
+
+	// Deleted comments stay in the list as placeholders while they still have replies
+	filter := bson.M{
+		"audiobookId": objID,
+		"$or":         bson.A{bson.M{"isDeleted": false}, bson.M{"replyCount": bson.M{"$gt": 0}}},
+	}
+	if parent := c.Query("parentId"); parent != "" {
+		parentID, err := primitive.ObjectIDFromHex(parent)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parent ID"})
+			return
+		}
+		filter["parentId"] = parentID
+	} else {
+		filter["parentId"] = nil
+	}
 
-	cursor, _ := cc.CommentCol.Find(context.TODO(), bson.M{"audiobookId": objID, "isDeleted": false})
-	var comments []models.Comment
+	if raw := c.Query("cursor"); raw != "" {
+		after, err := decodeCommentCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		filter["$and"] = bson.A{after.filter(sortName)}
+	}
+
+	// One extra comment tells whether there is another page
+	cursor, err := cc.CommentCol.Find(context.TODO(), filter, options.Find().SetSort(sort).SetLimit(int64(limit+1)))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+	comments := []models.Comment{}
 	cursor.All(context.TODO(), &comments)
-	c.JSON(http.StatusOK, comments)
+
+	nextCursor := ""
+	if len(comments) > limit {
+		comments = comments[:limit]
+		nextCursor = cursorAfterComment(comments[limit-1]).encode()
+	}
+	for i := range comments {
+		if comments[i].IsDeleted {
+			comments[i].Message = ""
+			comments[i].UserName = ""
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": comments, "nextCursor": nextCursor})
 }
 
-func (cc *CommentController) DeleteComment(c *gin.Context) {
-	audiobookID := c.Param("id") // Changed from streamID
-	commentID := c.Param("commentId")
-	objAudiobookID, err := primitive.ObjectIDFromHex(audiobookID) // Changed from objStreamID
+// EditComment - authenticated endpoint for the author to change their comment within the edit window.
+// Admins can edit any comment at any time. Earlier versions are kept in the comment's edit history.
+func (cc *CommentController) EditComment(c *gin.Context) {
+	var req request.EditCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Convert commentID into ObjectID
-	objID, err := primitive.ObjectIDFromHex(commentID)
+	comment, uid, isModerator, ok := cc.loadOwnComment(c)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	if !isModerator && now.Sub(comment.Timestamp) > commentEditWindow {
+		c.JSON(http.StatusForbidden, gin.H{"error": "The edit window for this comment has closed"})
+		return
+	}
+	if req.Message == comment.Message {
+		c.JSON(http.StatusOK, comment)
+		return
+	}
+
+	// The message filter makes concurrent edits fail instead of losing a version from the history
+	var updated models.Comment
+	err := cc.CommentCol.FindOneAndUpdate(context.TODO(),
+		bson.M{"_id": comment.ID, "message": comment.Message, "isDeleted": false},
+		bson.M{
+			"$set": bson.M{"message": req.Message, "editedAt": now, "updatedAt": now},
+			"$push": bson.M{"edits": bson.M{
+				"$each":  bson.A{models.CommentEdit{Message: comment.Message, EditedAt: now, EditedBy: uid}},
+				"$slice": -maxCommentEdits,
+			}},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusConflict, gin.H{"error": "Comment was changed meanwhile, reload and try again"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to edit comment"})
+		return
+	}
+
+	publishCommentEvent(cc.Redis, commentEdited, updated)
+	c.JSON(http.StatusOK, updated)
+}
+
+// GetCommentHistory - public endpoint listing the earlier versions of an edited comment, oldest first
+func (cc *CommentController) GetCommentHistory(c *gin.Context) {
+	audiobookID, ok := resolveAudiobookParam(c, cc.SlugCol)
+	if !ok {
+		return
+	}
+	objID, err := primitive.ObjectIDFromHex(c.Param("commentId"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
 		return
 	}
 
-	filter := bson.M{"_id": objID, "audiobookId": objAudiobookID} // Changed from streamId
-	result, err := cc.CommentCol.DeleteOne(context.TODO(), filter)
+	var comment models.Comment
+	err = cc.CommentCol.FindOne(context.TODO(), bson.M{"_id": objID, "audiobookId": audiobookID, "isDeleted": false}).Decode(&comment)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	edits := comment.Edits
+	if edits == nil {
+		edits = []models.CommentEdit{}
+	}
+	c.JSON(http.StatusOK, gin.H{"message": comment.Message, "editedAt": comment.EditedAt, "edits": edits})
+}
+
+// DeleteComment - authenticated endpoint for the author or an admin. The comment is soft deleted;
+// if it has replies it stays in its thread as a tombstone.
+func (cc *CommentController) DeleteComment(c *gin.Context) {
+	comment, uid, _, ok := cc.loadOwnComment(c)
+	if !ok {
 		return
 	}
 
-	if result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found or unauthorized"})
+	if err := cc.removeComment(context.TODO(), comment, uid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
 		return
 	}
 
+	comment.IsDeleted = true
+	publishCommentEvent(cc.Redis, commentDeleted, comment)
 	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
 }
+
+// loadOwnComment fetches the comment addressed by the route for a change by the current user,
+// who must be its author or an admin. Writes the error response and returns false otherwise.
+func (cc *CommentController) loadOwnComment(c *gin.Context) (models.Comment, primitive.ObjectID, bool, bool) {
+	var comment models.Comment
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return comment, uid, false, false
+	}
+	audiobookID, ok := resolveAudiobookParam(c, cc.SlugCol)
+	if !ok {
+		return comment, uid, false, false
+	}
+	objID, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return comment, uid, false, false
+	}
+
+	err = cc.CommentCol.FindOne(context.TODO(), bson.M{"_id": objID, "audiobookId": audiobookID, "isDeleted": false}).Decode(&comment)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return comment, uid, false, false
+	}
+
+	var user models.User
+	if err := cc.UserCol.FindOne(context.TODO(), bson.M{"_id": uid}).Decode(&user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user info"})
+		return comment, uid, false, false
+	}
+	if comment.UserID != uid && !user.IsAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only change your own comments"})
+		return comment, uid, false, false
+	}
+	return comment, uid, user.IsAdmin, true
+}
+
+// removeComment soft deletes a comment, clearing its text and edit history. A comment with replies
+// stays listed as a tombstone so its thread survives; otherwise it no longer counts as a reply.
+func (cc *CommentController) removeComment(ctx context.Context, comment models.Comment, deletedBy primitive.ObjectID) error {
+	now := time.Now()
+	result, err := cc.CommentCol.UpdateOne(ctx, bson.M{"_id": comment.ID, "isDeleted": false}, bson.M{
+		"$set": bson.M{
+			"isDeleted": true,
+			"message":   "",
+			"deletedAt": now,
+			"deletedBy": deletedBy,
+			"updatedAt": now,
+		},
+		"$unset": bson.M{"edits": ""},
+	})
+	// Only the delete that actually happened updates the thread
+	if err != nil || result.ModifiedCount == 0 {
+		return err
+	}
+
+	// Replies may have come or gone since the comment was loaded
+	var current models.Comment
+	if err := cc.CommentCol.FindOne(ctx, bson.M{"_id": comment.ID}).Decode(&current); err != nil || current.ReplyCount > 0 {
+		return err
+	}
+	cc.detachReply(ctx, comment.ParentID)
+	return nil
+}
+
+// detachReply lowers a parent's reply count after one of its replies disappeared from the thread.
+// A tombstone left without replies disappears as well, so its own parent is updated in turn.
+func (cc *CommentController) detachReply(ctx context.Context, parentID *primitive.ObjectID) {
+	for parentID != nil {
+		var parent models.Comment
+		err := cc.CommentCol.FindOneAndUpdate(ctx,
+			bson.M{"_id": *parentID},
+			bson.M{"$inc": bson.M{"replyCount": -1}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&parent)
+		if err != nil || !parent.IsDeleted || parent.ReplyCount > 0 {
+			return
+		}
+		parentID = parent.ParentID
+	}
+}
+
+// BackfillCommentThreads gives comments written before threading their reply fields,
+// so they sort and paginate with the rest
+func (cc *CommentController) BackfillCommentThreads() {
+	_, err := cc.CommentCol.UpdateMany(context.TODO(),
+		bson.M{"replyCount": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"replyCount": 0, "depth": 0}},
+	)
+	if err != nil {
+		log.Println("Comment backfill: failed to update comments:", err)
+	}
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	models "live_stream/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCommentCursorRoundTrip(t *testing.T) {
+	comment := models.Comment{
+		ID:         primitive.NewObjectID(),
+		Timestamp:  time.Date(2026, 3, 10, 12, 30, 45, 123456789, time.UTC),
+		ReplyCount: 4,
+	}
+	want := cursorAfterComment(comment)
+
+	got, err := decodeCommentCursor(want.encode())
+	if err != nil {
+		t.Fatalf("decodeCommentCursor() error = %v", err)
+	}
+	if !got.Timestamp.Equal(want.Timestamp) || got.ID != want.ID || got.ReplyCount != want.ReplyCount {
+		t.Errorf("decodeCommentCursor() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCommentCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "%%%"},
+		{"not json", "bm90IGpzb24"},
+		{"bad object id", "eyJpZCI6Inh5eiJ9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCommentCursor(tt.token); err == nil {
+				t.Errorf("decodeCommentCursor(%q) expected an error", tt.token)
+			}
+		})
+	}
+}
+
+func TestCommentCursorFilter(t *testing.T) {
+	cur := commentCursor{
+		Timestamp:  time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC),
+		ID:         primitive.NewObjectID(),
+		ReplyCount: 2,
+	}
+	after := func(op string) bson.A {
+		return bson.A{
+			bson.M{"timestamp": bson.M{op: cur.Timestamp}},
+			bson.M{"timestamp": cur.Timestamp, "_id": bson.M{op: cur.ID}},
+		}
+	}
+
+	tests := []struct {
+		sort string
+		want bson.M
+	}{
+		{"newest", bson.M{"$or": after("$lt")}},
+		{"oldest", bson.M{"$or": after("$gt")}},
+		{"top", bson.M{"$or": bson.A{
+			bson.M{"replyCount": bson.M{"$lt": 2}},
+			bson.M{"replyCount": 2, "$or": after("$lt")},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			if _, ok := commentSorts[tt.sort]; !ok {
+				t.Fatalf("sort %q is not offered", tt.sort)
+			}
+			if got := cur.filter(tt.sort); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter(%q) = %v, want %v", tt.sort, got, tt.want)
+			}
+		})
+	}
+}
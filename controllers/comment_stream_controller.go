@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	models "live_stream/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/websocket"
+)
+
+// Comment event types pushed to live subscribers
+const (
+	commentCreated = "comment.created"
+	commentEdited  = "comment.edited"
+	commentDeleted = "comment.deleted"
+)
+
+const (
+	commentChannelPrefix    = "comments:"
+	commentStreamKeepAlive  = 25 * time.Second // Below common proxy idle timeouts
+	commentStreamBuffer     = 32               // Events a client may fall behind before it is dropped
+	maxCommentStreamClients = 10000            // Per instance
+)
+
+// commentEvent is published on the audiobook's Redis channel, so every server instance
+// can forward it to the clients connected to it
+type commentEvent struct {
+	Type    string         `json:"type"`
+	Comment models.Comment `json:"comment"`
+}
+
+func commentChannel(audiobookID primitive.ObjectID) string {
+	return commentChannelPrefix + audiobookID.Hex()
+}
+
+// publishCommentEvent announces a comment change. Deleted comments are sent without their text.
+func publishCommentEvent(rdb *redis.Client, eventType string, comment models.Comment) {
+	if rdb == nil {
+		return
+	}
+	if comment.IsDeleted {
+		comment.Message = ""
+		comment.UserName = ""
+	}
+	payload, err := json.Marshal(commentEvent{Type: eventType, Comment: comment})
+	if err != nil {
+		return
+	}
+	if err := rdb.Publish(context.TODO(), commentChannel(comment.AudiobookID), payload).Err(); err != nil {
+		log.Println("Failed to publish comment event:", err)
+	}
+}
+
+// RunCommentHub holds this instance's single Redis subscription to all comment channels and
+// forwards each event to the local stream clients of that audiobook.
+// It blocks, so start it in its own goroutine.
+func (cc *CommentController) RunCommentHub() {
+	if cc.Redis == nil {
+		return
+	}
+	hub := cc.commentHub()
+
+	// go-redis resubscribes by itself after connection loss
+	pubsub := cc.Redis.PSubscribe(context.Background(), commentChannelPrefix+"*")
+	defer pubsub.Close()
+	for msg := range pubsub.Channel() {
+		audiobookID, err := primitive.ObjectIDFromHex(strings.TrimPrefix(msg.Channel, commentChannelPrefix))
+		if err != nil {
+			continue
+		}
+		var event commentEvent
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			continue
+		}
+		hub.broadcast(audiobookID, streamedCommentEvent{Type: event.Type, Payload: msg.Payload})
+	}
+}
+
+// streamedCommentEvent is a comment event as handed to one stream client
+type streamedCommentEvent struct {
+	Type    string
+	Payload string // JSON encoded commentEvent
+}
+
+// commentHub tracks the stream clients connected to this instance, per audiobook
+type commentHub struct {
+	mu          sync.Mutex
+	subscribers map[primitive.ObjectID]map[chan streamedCommentEvent]struct{}
+	count       int
+}
+
+func (cc *CommentController) commentHub() *commentHub {
+	cc.hubOnce.Do(func() {
+		cc.hub = &commentHub{subscribers: map[primitive.ObjectID]map[chan streamedCommentEvent]struct{}{}}
+	})
+	return cc.hub
+}
+
+// subscribe registers a client, or returns nil when the instance already serves too many
+func (h *commentHub) subscribe(audiobookID primitive.ObjectID) chan streamedCommentEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count >= maxCommentStreamClients {
+		return nil
+	}
+	ch := make(chan streamedCommentEvent, commentStreamBuffer)
+	if h.subscribers[audiobookID] == nil {
+		h.subscribers[audiobookID] = map[chan streamedCommentEvent]struct{}{}
+	}
+	h.subscribers[audiobookID][ch] = struct{}{}
+	h.count++
+	return ch
+}
+
+// unsubscribe removes a client; its channel is closed unless broadcast already dropped it
+func (h *commentHub) unsubscribe(audiobookID primitive.ObjectID, ch chan streamedCommentEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(audiobookID, ch)
+}
+
+func (h *commentHub) remove(audiobookID primitive.ObjectID, ch chan streamedCommentEvent) {
+	subs := h.subscribers[audiobookID]
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+	delete(subs, ch)
+	if len(subs) == 0 {
+		delete(h.subscribers, audiobookID)
+	}
+	h.count--
+	close(ch)
+}
+
+// broadcast hands an event to every client of the audiobook. Clients too slow to keep up are
+// disconnected rather than silently missing events, so they reconnect and reload.
+func (h *commentHub) broadcast(audiobookID primitive.ObjectID, event streamedCommentEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers[audiobookID] {
+		select {
+		case ch <- event:
+		default:
+			h.remove(audiobookID, ch)
+		}
+	}
+}
+
+// commentStreamServer accepts WebSocket handshakes from native clients, which send no Origin.
+// Browser origins have already been checked by the CORS middleware.
+var commentStreamServer = websocket.Server{
+	Handshake: func(config *websocket.Config, req *http.Request) error {
+		if origin := req.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil {
+				return err
+			}
+			config.Origin = u
+		}
+		return nil
+	},
+}
+
+// StreamComments - public endpoint pushing comment create/edit/delete events of an audiobook as they
+// happen. WebSocket upgrade requests get one JSON event per message; everyone else gets
+// Server-Sent Events named after the event type.
+func (cc *CommentController) StreamComments(c *gin.Context) {
+	objID, ok := resolveAudiobookParam(c, cc.SlugCol)
+	if !ok || !cc.requirePublishedAudiobook(c, objID) {
+		return
+	}
+	if cc.Redis == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live comments are unavailable"})
+		return
+	}
+
+	hub := cc.commentHub()
+	events := hub.subscribe(objID)
+	if events == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Too many live comment connections, try again later"})
+		return
+	}
+	defer hub.unsubscribe(objID, events)
+
+	if c.IsWebsocket() {
+		server := commentStreamServer
+		server.Handler = func(ws *websocket.Conn) {
+			streamCommentsWebSocket(ws, events)
+		}
+		server.ServeHTTP(c.Writer, c.Request)
+		return
+	}
+	streamCommentsSSE(c, events)
+}
+
+// streamCommentsSSE forwards events until the client disconnects, with periodic keep-alives
+func streamCommentsSSE(c *gin.Context, events <-chan streamedCommentEvent) {
+	ctx := c.Request.Context()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	keepAlive := time.NewTicker(commentStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event.Payload)
+			return true
+		case <-keepAlive.C:
+			io.WriteString(w, ": keep-alive\n\n")
+			return true
+		}
+	})
+}
+
+// streamCommentsWebSocket forwards events until either side closes the connection
+func streamCommentsWebSocket(ws *websocket.Conn, events <-chan streamedCommentEvent) {
+	defer ws.Close()
+	ctx, cancel := context.WithCancel(ws.Request().Context())
+	defer cancel()
+
+	// Clients don't send anything; reading only notices when they go away
+	go func() {
+		var discard string
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+		cancel()
+	}()
+
+	keepAlive := time.NewTicker(commentStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := websocket.Message.Send(ws, event.Payload); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if err := websocket.Message.Send(ws, `{"type":"keep-alive"}`); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	models "live_stream/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxTimelineComments  = 200
+	defaultMarkerBuckets = 100
+	maxMarkerBuckets     = 500
+)
+
+// validAnchor checks a comment's playback position against the audiobook's running time, when known
+func (cc *CommentController) validAnchor(audiobookID primitive.ObjectID, position *float64, chapter *int) bool {
+	if chapter != nil && *chapter < 0 {
+		return false
+	}
+	if position == nil {
+		return true
+	}
+	if *position < 0 || math.IsNaN(*position) {
+		return false
+	}
+
+	var audiobook models.Audiobook
+	err := cc.AudiobookCol.FindOne(context.TODO(), bson.M{"_id": audiobookID},
+		options.FindOne().SetProjection(bson.M{"durationSeconds": 1}),
+	).Decode(&audiobook)
+	if err != nil {
+		return false
+	}
+	return audiobook.DurationSeconds == 0 || *position <= float64(audiobook.DurationSeconds)
+}
+
+// anchoredCommentsFilter matches the visible top-level comments of an audiobook that have a position
+func anchoredCommentsFilter(audiobookID primitive.ObjectID) bson.M {
+	return bson.M{
+		"audiobookId":     audiobookID,
+		"parentId":        nil,
+		"positionSeconds": bson.M{"$exists": true},
+		"$or":             bson.A{bson.M{"isDeleted": false}, bson.M{"replyCount": bson.M{"$gt": 0}}},
+	}
+}
+
+// timelineCursor is the position of the last comment of a timeline page, handed to clients as an opaque token
+type timelineCursor struct {
+	Position float64            `json:"p"`
+	ID       primitive.ObjectID `json:"id"`
+}
+
+func (cur timelineCursor) encode() string {
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeTimelineCursor(token string) (timelineCursor, error) {
+	var cur timelineCursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cur, err
+	}
+	err = json.Unmarshal(raw, &cur)
+	return cur, err
+}
+
+// filter matches the comments that come after the cursor in timeline order
+func (cur timelineCursor) filter() bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"positionSeconds": bson.M{"$gt": cur.Position}},
+		bson.M{"positionSeconds": cur.Position, "_id": bson.M{"$gt": cur.ID}},
+	}}
+}
+
+// GetTimelineComments - public endpoint returning the top-level comments anchored between ?from= and
+// ?to= seconds (both optional), in timeline order. ?chapter= narrows them to one chapter.
+// Pages are fetched with ?limit= and the nextCursor of the previous page as ?cursor=.
+// Replies are fetched through GetComments with ?parentId=.
+func (cc *CommentController) GetTimelineComments(c *gin.Context) {
+	objID, ok := resolveAudiobookParam(c, cc.SlugCol)
+	if !ok || !cc.requirePublishedAudiobook(c, objID) {
+		return
+	}
+
+	filter := anchoredCommentsFilter(objID)
+	window := bson.M{"$exists": true}
+	if from := c.Query("from"); from != "" {
+		seconds, err := strconv.ParseFloat(from, 64)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a number of seconds"})
+			return
+		}
+		window["$gte"] = seconds
+	}
+	if to := c.Query("to"); to != "" {
+		seconds, err := strconv.ParseFloat(to, 64)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a number of seconds"})
+			return
+		}
+		window["$lt"] = seconds
+	}
+	filter["positionSeconds"] = window
+	if chapter := c.Query("chapter"); chapter != "" {
+		n, err := strconv.Atoi(chapter)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid chapter"})
+			return
+		}
+		filter["chapter"] = n
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > maxTimelineComments {
+		limit = 100
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		after, err := decodeTimelineCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		filter["$and"] = bson.A{after.filter()}
+	}
+
+	// One extra comment tells whether there is another page
+	cursor, err := cc.CommentCol.Find(context.TODO(), filter,
+		options.Find().SetSort(bson.D{{Key: "positionSeconds", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit+1)),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+	comments := []models.Comment{}
+	cursor.All(context.TODO(), &comments)
+
+	nextCursor := ""
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[limit-1]
+		nextCursor = timelineCursor{Position: *last.Position, ID: last.ID}.encode()
+	}
+	for i := range comments {
+		if comments[i].IsDeleted {
+			comments[i].UserName = ""
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": comments, "nextCursor": nextCursor})
+}
+
+// GetCommentMarkers - public endpoint splitting the audio timeline into ?buckets= equal slices and
+// counting the anchored comments in each, for drawing markers on the player. Without a known running
+// time the timeline ends at the last anchored comment.
+func (cc *CommentController) GetCommentMarkers(c *gin.Context) {
+	objID, ok := resolveAudiobookParam(c, cc.SlugCol)
+	if !ok {
+		return
+	}
+
+	buckets, err := strconv.Atoi(c.DefaultQuery("buckets", strconv.Itoa(defaultMarkerBuckets)))
+	if err != nil || buckets <= 0 || buckets > maxMarkerBuckets {
+		buckets = defaultMarkerBuckets
+	}
+
+	ctx := context.TODO()
+	published := publishedFilter(time.Now())
+	published["_id"] = objID
+	var audiobook models.Audiobook
+	err = cc.AudiobookCol.FindOne(ctx, published,
+		options.FindOne().SetProjection(bson.M{"durationSeconds": 1}),
+	).Decode(&audiobook)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return
+	}
+
+	// Tombstones stay listed for their replies, but are no longer comments at that moment
+	filter := anchoredCommentsFilter(objID)
+	filter["isDeleted"] = false
+	duration := float64(audiobook.DurationSeconds)
+	if duration == 0 {
+		var last models.Comment
+		err := cc.CommentCol.FindOne(ctx, filter,
+			options.FindOne().SetSort(bson.M{"positionSeconds": -1}).SetProjection(bson.M{"positionSeconds": 1}),
+		).Decode(&last)
+		if err == nil && last.Position != nil {
+			duration = math.Ceil(*last.Position)
+		}
+	}
+
+	counts := make([]int, buckets)
+	if duration == 0 {
+		c.JSON(http.StatusOK, gin.H{"durationSeconds": 0, "bucketSeconds": 0, "counts": counts})
+		return
+	}
+	bucketSeconds := duration / float64(buckets)
+
+	cursor, err := cc.CommentCol.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$floor": bson.M{"$divide": bson.A{"$positionSeconds", bucketSeconds}}},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment markers"})
+		return
+	}
+	var rows []struct {
+		Bucket float64 `bson:"_id"`
+		Count  int     `bson:"count"`
+	}
+	cursor.All(ctx, &rows)
+	for _, r := range rows {
+		// A comment at the very end belongs to the last bucket
+		i := min(int(r.Bucket), buckets-1)
+		if i >= 0 {
+			counts[i] += r.Count
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"durationSeconds": duration, "bucketSeconds": bucketSeconds, "counts": counts})
+}
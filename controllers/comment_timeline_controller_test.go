@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTimelineCursorRoundTrip(t *testing.T) {
+	tests := []timelineCursor{
+		{Position: 0, ID: primitive.NewObjectID()},
+		{Position: 754.25, ID: primitive.NewObjectID()},
+	}
+	for _, want := range tests {
+		got, err := decodeTimelineCursor(want.encode())
+		if err != nil {
+			t.Fatalf("decodeTimelineCursor() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("decodeTimelineCursor() = %+v, want %+v", got, want)
+		}
+	}
+
+	if _, err := decodeTimelineCursor("%%%"); err == nil {
+		t.Error("decodeTimelineCursor() accepted an invalid token")
+	}
+}
+
+func TestTimelineCursorFilter(t *testing.T) {
+	cur := timelineCursor{Position: 90.5, ID: primitive.NewObjectID()}
+	want := bson.M{"$or": bson.A{
+		bson.M{"positionSeconds": bson.M{"$gt": 90.5}},
+		bson.M{"positionSeconds": 90.5, "_id": bson.M{"$gt": cur.ID}},
+	}}
+	if got := cur.filter(); !reflect.DeepEqual(got, want) {
+		t.Errorf("filter() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	models "live_stream/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// contentPreferences are the mature content settings of the current viewer.
+// Anonymous viewers and users without settings are unrestricted.
+type contentPreferences struct {
+	MaxAgeRating   string
+	HiddenWarnings []string
+}
+
+// loadContentPreferences reads the settings of the logged-in user, if any
+func loadContentPreferences(c *gin.Context, userCol *mongo.Collection) contentPreferences {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		return contentPreferences{}
+	}
+
+	var user models.User
+	if err := userCol.FindOne(context.TODO(), bson.M{"_id": uid}).Decode(&user); err != nil {
+		return contentPreferences{}
+	}
+	return contentPreferences{MaxAgeRating: user.MaxAgeRating, HiddenWarnings: user.HiddenWarnings}
+}
+
+// apply adds the viewer's restrictions to an audiobook query
+func (p contentPreferences) apply(filter bson.M) bson.M {
+	if p.MaxAgeRating != "" {
+		allowed := bson.A{nil, ""}
+		for _, rating := range allowedAgeRatings(p.MaxAgeRating) {
+			allowed = append(allowed, rating)
+		}
+		filter["ageRating"] = bson.M{"$in": allowed}
+	}
+	if len(p.HiddenWarnings) > 0 {
+		filter["contentWarnings"] = bson.M{"$nin": p.HiddenWarnings}
+	}
+	return filter
+}
+
+// allows reports whether a single audiobook passes the viewer's restrictions
+func (p contentPreferences) allows(a models.Audiobook) bool {
+	if p.MaxAgeRating != "" && a.AgeRating != "" {
+		allowed := false
+		for _, rating := range allowedAgeRatings(p.MaxAgeRating) {
+			if rating == a.AgeRating {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	for _, hidden := range p.HiddenWarnings {
+		for _, warning := range a.ContentWarnings {
+			if hidden == warning {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// allowedAgeRatings returns every rating up to and including max
+func allowedAgeRatings(max string) []string {
+	for i, rating := range models.AgeRatings {
+		if rating == max {
+			return models.AgeRatings[:i+1]
+		}
+	}
+	return models.AgeRatings
+}
+
+func isValidAgeRating(rating string) bool {
+	for _, r := range models.AgeRatings {
+		if r == rating {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTags lowercases and de-duplicates tags such as content warnings and genres
+func normalizeTags(tags []string) []string {
+	seen := map[string]bool{}
+	normalized := []string{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !seen[t] {
+			seen[t] = true
+			normalized = append(normalized, t)
+		}
+	}
+	return normalized
+}
+
+// trimNames trims and de-duplicates display names such as authors, keeping their case
+func trimNames(names []string) []string {
+	seen := map[string]bool{}
+	trimmed := []string{}
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n != "" && !seen[n] {
+			seen[n] = true
+			trimmed = append(trimmed, n)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxEventsPerBatch = 500
+	maxEventIDLength  = 100
+	// A pending claim older than this belongs to a request that died; the event may be replayed
+	eventClaimTimeout = time.Minute
+
+	clientEventProgress = "progress"
+	clientEventSession  = "session"
+)
+
+// Outcome of each ingested event
+const (
+	eventApplied   = "applied"   // Folded into progress / listening history
+	eventStale     = "stale"     // Valid, but a newer position was already stored
+	eventDuplicate = "duplicate" // Already ingested from an earlier replay
+	eventRejected  = "rejected"  // Invalid; replaying it will not help
+	eventFailed    = "failed"    // Server error; safe to replay later
+)
+
+type eventIngestResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// IngestEvents - authenticated endpoint for players to replay events buffered while offline.
+// Every event carries a client-generated ID so replays are ignored; events are applied in the
+// order they happened (device clocks corrected by the batch's skew), progress with last write wins.
+// Event IDs are only remembered for models.IngestedEventRetention, so older events are rejected.
+func (uc *UserController) IngestEvents(c *gin.Context) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req request.IngestEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Events) > maxEventsPerBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many events in one batch"})
+		return
+	}
+
+	ctx := context.TODO()
+	now := time.Now()
+	skew := now.Sub(req.SentAt)
+
+	ids := make([]primitive.ObjectID, 0, len(req.Events))
+	for _, e := range req.Events {
+		ids = append(ids, e.AudiobookID)
+	}
+	durations, err := uc.audiobookDurations(ctx, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audiobooks"})
+		return
+	}
+
+	// Results keep the request order; processing follows the timeline
+	results := make([]eventIngestResult, len(req.Events))
+	order := make([]int, len(req.Events))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return req.Events[order[a]].OccurredAt.Before(req.Events[order[b]].OccurredAt)
+	})
+
+	for _, i := range order {
+		e := req.Events[i]
+		results[i] = eventIngestResult{ID: e.ID}
+
+		msg := ""
+		if len(e.ID) > maxEventIDLength {
+			msg = "Event ID is too long"
+		} else if e.Type != clientEventProgress && e.Type != clientEventSession {
+			msg = "Unknown event type"
+		} else if _, ok := durations[e.AudiobookID]; !ok {
+			msg = "Audiobook not found"
+		} else if now.Sub(reconcileClientTime(e.OccurredAt, skew, now)) > models.IngestedEventRetention {
+			msg = "Event is too old"
+		}
+		if msg != "" {
+			results[i].Status, results[i].Error = eventRejected, msg
+			continue
+		}
+
+		// Claim the event ID first: a concurrent or later replay of it stops here
+		claim, err := uc.claimEvent(ctx, uid, e, now)
+		if err != nil {
+			claim = eventFailed
+		}
+		if claim != "" {
+			results[i].Status = claim
+			continue
+		}
+
+		status, msg := uc.applyClientEvent(ctx, uid, req.DeviceID, e, skew, now, durations[e.AudiobookID])
+		results[i].Status, results[i].Error = status, msg
+
+		marker := bson.M{"userId": uid, "eventId": e.ID}
+		if status == eventFailed {
+			// Release the ID so the event can be replayed
+			uc.EventCol.DeleteOne(ctx, marker)
+		} else {
+			uc.EventCol.UpdateOne(ctx, marker, bson.M{"$set": bson.M{"status": status}})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results, "serverTime": now})
+}
+
+// claimEvent reserves an event ID for this request. It returns an empty status once claimed,
+// eventDuplicate if the event was ingested before, or eventFailed while another request is still
+// applying it. A claim left pending by a request that died before applying the event is taken over,
+// which is safe because applying is idempotent.
+func (uc *UserController) claimEvent(ctx context.Context, uid primitive.ObjectID, e request.ClientEvent, now time.Time) (string, error) {
+	_, err := uc.EventCol.InsertOne(ctx, models.IngestedEvent{
+		UserID:    uid,
+		EventID:   e.ID,
+		Type:      e.Type,
+		Status:    models.IngestedEventPending,
+		CreatedAt: now,
+	})
+	if err == nil {
+		return "", nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return "", err
+	}
+
+	takeover, err := uc.EventCol.UpdateOne(ctx,
+		bson.M{
+			"userId":    uid,
+			"eventId":   e.ID,
+			"status":    models.IngestedEventPending,
+			"createdAt": bson.M{"$lt": now.Add(-eventClaimTimeout)},
+		},
+		bson.M{"$set": bson.M{"createdAt": now}},
+	)
+	if err != nil {
+		return "", err
+	}
+	if takeover.ModifiedCount == 1 {
+		return "", nil
+	}
+
+	var marker models.IngestedEvent
+	err = uc.EventCol.FindOne(ctx, bson.M{"userId": uid, "eventId": e.ID}).Decode(&marker)
+	if err != nil || marker.Status == models.IngestedEventPending {
+		// In flight elsewhere, or released by a failed attempt meanwhile
+		return eventFailed, nil
+	}
+	return eventDuplicate, nil
+}
+
+// applyClientEvent folds one event into progress or listening history, returning its status
+// and, for rejected events, why
+func (uc *UserController) applyClientEvent(ctx context.Context, uid primitive.ObjectID, deviceID string, e request.ClientEvent, skew time.Duration, now time.Time, duration int) (string, string) {
+	occurredAt := reconcileClientTime(e.OccurredAt, skew, now)
+
+	switch e.Type {
+	case clientEventProgress:
+		applied, err := uc.saveProgress(ctx, uid, deviceID, request.ProgressUpdate{
+			AudiobookID:     e.AudiobookID,
+			PositionSeconds: e.PositionSeconds,
+			Chapter:         e.Chapter,
+			PlaybackSpeed:   e.PlaybackSpeed,
+			Completed:       e.Completed,
+		}, occurredAt, duration)
+		switch {
+		case err == errInvalidProgress:
+			return eventRejected, "Invalid position"
+		case err != nil:
+			return eventFailed, ""
+		case !applied:
+			return eventStale, ""
+		}
+		return eventApplied, ""
+
+	default:
+		if e.StartedAt.IsZero() {
+			return eventRejected, "startedAt is required for session events"
+		}
+		startedAt := reconcileClientTime(e.StartedAt, skew, now)
+		if msg := validateListeningSession(startedAt, occurredAt, e.SecondsListened, now); msg != "" {
+			return eventRejected, msg
+		}
+		_, err := uc.SessionCol.InsertOne(ctx, models.ListeningSession{
+			UserID:          uid,
+			AudiobookID:     e.AudiobookID,
+			StartedAt:       startedAt,
+			EndedAt:         occurredAt,
+			SecondsListened: e.SecondsListened,
+			DeviceID:        deviceID,
+			EventID:         e.ID,
+			CreatedAt:       now,
+		})
+		if mongo.IsDuplicateKeyError(err) {
+			// Inserted by an earlier attempt that died before recording the outcome
+			return eventApplied, ""
+		}
+		if err != nil {
+			return eventFailed, ""
+		}
+		return eventApplied, ""
+	}
+}
@@ -0,0 +1,428 @@
+package controllers
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	dayLayout = "2006-01-02"
+
+	// Players run at most this fast, so a session cannot hold more audio than this times its wall time
+	maxPlaybackSpeed = 3
+	// Tolerated device clock drift for session timestamps in the future
+	sessionClockTolerance = 5 * time.Minute
+
+	statsHistoryDays = 365
+	statsWeeks       = 12
+	statsTopN        = 5
+)
+
+// namedMinutes is an entry of a top list, e.g. a genre and the minutes spent on it
+type namedMinutes struct {
+	Name    string `json:"name"`
+	Minutes int    `json:"minutes"`
+}
+
+// audiobookListening is the time a user spent on one audiobook
+type audiobookListening struct {
+	AudiobookID primitive.ObjectID `bson:"_id"`
+	Seconds     int                `bson:"seconds"`
+}
+
+// RecordListeningSession - authenticated endpoint for players to report a listening session
+func (uc *UserController) RecordListeningSession(c *gin.Context) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req request.ListeningSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	if msg := validateListeningSession(req.StartedAt, req.EndedAt, req.SecondsListened, now); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	count, err := uc.StreamCol.CountDocuments(context.TODO(), bson.M{"_id": req.AudiobookID, "deletedAt": nil})
+	if err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return
+	}
+
+	session := models.ListeningSession{
+		UserID:          uid,
+		AudiobookID:     req.AudiobookID,
+		StartedAt:       req.StartedAt,
+		EndedAt:         req.EndedAt,
+		SecondsListened: req.SecondsListened,
+		DeviceID:        req.DeviceID,
+		CreatedAt:       now,
+	}
+	result, err := uc.SessionCol.InsertOne(context.TODO(), session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record session"})
+		return
+	}
+	session.ID = result.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusOK, session)
+}
+
+// validateListeningSession returns an error message for an implausible session, or "" if it is valid
+func validateListeningSession(startedAt, endedAt time.Time, secondsListened int, now time.Time) string {
+	wall := endedAt.Sub(startedAt)
+	switch {
+	case wall <= 0:
+		return "Session must end after it starts"
+	case endedAt.After(now.Add(sessionClockTolerance)):
+		return "Session ends in the future"
+	case secondsListened <= 0 || float64(secondsListened) > maxPlaybackSpeed*wall.Seconds()+1:
+		return "Seconds listened does not fit the session"
+	}
+	return ""
+}
+
+// GetListeningHistory - authenticated endpoint listing the user's most recent listening sessions
+func (uc *UserController) GetListeningHistory(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	cursor, err := uc.SessionCol.Find(context.TODO(), bson.M{"userId": uid},
+		options.Find().SetSort(bson.M{"startedAt": -1}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch history"})
+		return
+	}
+	sessions := []models.ListeningSession{}
+	cursor.All(context.TODO(), &sessions)
+	c.JSON(http.StatusOK, sessions)
+}
+
+// loadTimeZone resolves an IANA time zone name. "Local" is refused: it names the server's zone,
+// which the database doesn't know by that name.
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %s", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// GetListeningStats - authenticated endpoint with the user's listening statistics: minutes per day
+// (?days=, default 30) and per week, current and longest streak, titles finished and favorite genres.
+// Days are counted in ?tz= (IANA name, default UTC).
+func (uc *UserController) GetListeningStats(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	loc, err := loadTimeZone(c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+		return
+	}
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 || days > statsHistoryDays {
+		days = 30
+	}
+
+	ctx := context.TODO()
+	today := startOfDay(time.Now().In(loc))
+	from := today.AddDate(0, 0, -statsHistoryDays+1)
+	to := today.AddDate(0, 0, 1)
+
+	byDay, err := uc.listeningByDay(ctx, uid, from, to, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute stats"})
+		return
+	}
+
+	daily := make([]gin.H, 0, days)
+	for d := today.AddDate(0, 0, -days+1); !d.After(today); d = d.AddDate(0, 0, 1) {
+		day := d.Format(dayLayout)
+		daily = append(daily, gin.H{"date": day, "minutes": byDay[day] / 60})
+	}
+
+	// Weeks start on Monday
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	weekly := make([]gin.H, 0, statsWeeks)
+	for w := statsWeeks - 1; w >= 0; w-- {
+		start := weekStart.AddDate(0, 0, -7*w)
+		seconds := 0
+		for d := 0; d < 7; d++ {
+			seconds += byDay[start.AddDate(0, 0, d).Format(dayLayout)]
+		}
+		weekly = append(weekly, gin.H{"weekStart": start.Format(dayLayout), "minutes": seconds / 60})
+	}
+
+	current, longest := listeningStreaks(byDay, from, today)
+
+	finished, _ := uc.ShelfItemCol.CountDocuments(ctx, bson.M{"userId": uid, "shelf": models.ShelfFinished})
+
+	totals, err := uc.listeningByAudiobook(ctx, uid, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute stats"})
+		return
+	}
+	_, genres, _ := uc.rankListening(ctx, totals)
+
+	totalSeconds := 0
+	for _, s := range byDay {
+		totalSeconds += s
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"timeZone":       loc.String(),
+		"totalMinutes":   totalSeconds / 60, // Over the last year
+		"daily":          daily,
+		"weekly":         weekly,
+		"currentStreak":  current,
+		"longestStreak":  longest,
+		"titlesFinished": finished,
+		"favoriteGenres": genres,
+	})
+}
+
+// GetWrapped - authenticated endpoint summarizing a year of listening (?year=, default this year;
+// ?tz= as for GetListeningStats): total time, top titles, genres and authors, streaks and habits
+func (uc *UserController) GetWrapped(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	loc, err := loadTimeZone(c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+		return
+	}
+	now := time.Now().In(loc)
+	year, err := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(now.Year())))
+	if err != nil || year < 2000 || year > now.Year() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		return
+	}
+
+	ctx := context.TODO()
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	to := from.AddDate(1, 0, 0)
+
+	byDay, err := uc.listeningByDay(ctx, uid, from, to, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute summary"})
+		return
+	}
+	totals, err := uc.listeningByAudiobook(ctx, uid, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute summary"})
+		return
+	}
+	titles, genres, authors := uc.rankListening(ctx, totals)
+
+	totalSeconds := 0
+	months := map[time.Month]int{}
+	weekdays := map[time.Weekday]int{}
+	for day, seconds := range byDay {
+		d, err := time.ParseInLocation(dayLayout, day, loc)
+		if err != nil {
+			continue
+		}
+		totalSeconds += seconds
+		months[d.Month()] += seconds
+		weekdays[d.Weekday()] += seconds
+	}
+
+	last := to.AddDate(0, 0, -1)
+	if last.After(now) {
+		last = startOfDay(now)
+	}
+	_, longest := listeningStreaks(byDay, from, last)
+
+	finished, _ := uc.ShelfItemCol.CountDocuments(ctx, bson.M{
+		"userId":  uid,
+		"shelf":   models.ShelfFinished,
+		"addedAt": bson.M{"$gte": from, "$lt": to},
+	})
+
+	summary := gin.H{
+		"year":           year,
+		"totalMinutes":   totalSeconds / 60,
+		"daysListened":   len(byDay),
+		"titlesStarted":  len(totals),
+		"titlesFinished": finished,
+		"longestStreak":  longest,
+		"topAudiobooks":  titles,
+		"topGenres":      genres,
+		"topAuthors":     authors,
+	}
+	if month, ok := maxKey(months); ok {
+		summary["mostActiveMonth"] = month.String()
+	}
+	if weekday, ok := maxKey(weekdays); ok {
+		summary["favoriteWeekday"] = weekday.String()
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// listeningByDay sums the seconds listened per local day ("2006-01-02") in [from, to)
+func (uc *UserController) listeningByDay(ctx context.Context, uid primitive.ObjectID, from, to time.Time, loc *time.Location) (map[string]int, error) {
+	cursor, err := uc.SessionCol.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": uid, "startedAt": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$dateToString": bson.M{
+				"format":   "%Y-%m-%d",
+				"date":     "$startedAt",
+				"timezone": loc.String(),
+			}},
+			"seconds": bson.M{"$sum": "$secondsListened"},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		Day     string `bson:"_id"`
+		Seconds int    `bson:"seconds"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	byDay := make(map[string]int, len(rows))
+	for _, r := range rows {
+		byDay[r.Day] = r.Seconds
+	}
+	return byDay, nil
+}
+
+// listeningByAudiobook sums the seconds listened per audiobook in [from, to), most listened first
+func (uc *UserController) listeningByAudiobook(ctx context.Context, uid primitive.ObjectID, from, to time.Time) ([]audiobookListening, error) {
+	cursor, err := uc.SessionCol.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": uid, "startedAt": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$audiobookId", "seconds": bson.M{"$sum": "$secondsListened"}}}},
+		{{Key: "$sort", Value: bson.M{"seconds": -1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var totals []audiobookListening
+	err = cursor.All(ctx, &totals)
+	return totals, err
+}
+
+// rankListening turns per-audiobook listening time into top titles, genres and authors
+func (uc *UserController) rankListening(ctx context.Context, totals []audiobookListening) (titles, genres, authors []namedMinutes) {
+	titles, genres, authors = []namedMinutes{}, []namedMinutes{}, []namedMinutes{}
+	if len(totals) == 0 {
+		return
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(totals))
+	for _, t := range totals {
+		ids = append(ids, t.AudiobookID)
+	}
+	cursor, err := uc.StreamCol.Find(ctx, bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"name": 1, "genres": 1, "authors": 1}),
+	)
+	if err != nil {
+		return
+	}
+	var audiobooks []models.Audiobook
+	cursor.All(ctx, &audiobooks)
+	byID := make(map[primitive.ObjectID]models.Audiobook, len(audiobooks))
+	for _, a := range audiobooks {
+		byID[a.ID] = a
+	}
+
+	genreSeconds, authorSeconds := map[string]int{}, map[string]int{}
+	for _, t := range totals {
+		a, ok := byID[t.AudiobookID]
+		if !ok {
+			continue
+		}
+		if len(titles) < statsTopN {
+			titles = append(titles, namedMinutes{Name: a.Name, Minutes: t.Seconds / 60})
+		}
+		for _, g := range a.Genres {
+			genreSeconds[g] += t.Seconds
+		}
+		for _, au := range a.Authors {
+			authorSeconds[au] += t.Seconds
+		}
+	}
+	return titles, topMinutes(genreSeconds), topMinutes(authorSeconds)
+}
+
+// topMinutes returns the statsTopN names with the most seconds, in minutes
+func topMinutes(seconds map[string]int) []namedMinutes {
+	top := make([]namedMinutes, 0, len(seconds))
+	for name, s := range seconds {
+		top = append(top, namedMinutes{Name: name, Minutes: s / 60})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Minutes != top[j].Minutes {
+			return top[i].Minutes > top[j].Minutes
+		}
+		return top[i].Name < top[j].Name
+	})
+	if len(top) > statsTopN {
+		top = top[:statsTopN]
+	}
+	return top
+}
+
+// listeningStreaks returns the streak of consecutive listening days ending today (or yesterday,
+// so the streak survives until the day is over) and the longest streak between from and today
+func listeningStreaks(byDay map[string]int, from, today time.Time) (current, longest int) {
+	run := 0
+	for d := from; !d.After(today); d = d.AddDate(0, 0, 1) {
+		if byDay[d.Format(dayLayout)] > 0 {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+
+	d := today
+	if byDay[d.Format(dayLayout)] == 0 {
+		d = d.AddDate(0, 0, -1)
+	}
+	for ; !d.Before(from) && byDay[d.Format(dayLayout)] > 0; d = d.AddDate(0, 0, -1) {
+		current++
+	}
+	return current, longest
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// maxKey returns the key with the largest value, the lowest such key on a tie
+func maxKey[K cmp.Ordered](values map[K]int) (K, bool) {
+	var best K
+	found, bestValue := false, 0
+	for k, v := range values {
+		if !found || v > bestValue || (v == bestValue && k < best) {
+			best, bestValue, found = k, v, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListeningStreaks(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, -9)
+
+	tests := []struct {
+		name        string
+		byDay       map[string]int
+		wantCurrent int
+		wantLongest int
+	}{
+		{"nothing", map[string]int{}, 0, 0},
+		{"today only", map[string]int{"2026-03-10": 5}, 1, 1},
+		{"ending yesterday", map[string]int{"2026-03-08": 5, "2026-03-09": 5}, 2, 2},
+		{"broken two days ago", map[string]int{"2026-03-07": 5, "2026-03-08": 5}, 0, 2},
+		{
+			"longest in the past",
+			map[string]int{"2026-03-01": 1, "2026-03-02": 1, "2026-03-03": 1, "2026-03-09": 1, "2026-03-10": 1},
+			2, 3,
+		},
+		{"zero minutes break the run", map[string]int{"2026-03-09": 0, "2026-03-10": 3}, 1, 1},
+		{"before the window ignored", map[string]int{"2026-02-28": 1, "2026-03-01": 1}, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := listeningStreaks(tt.byDay, from, today)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("listeningStreaks() = %d, %d, want %d, %d", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestMaxKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]int
+		want   string
+		wantOK bool
+	}{
+		{"empty", map[string]int{}, "", false},
+		{"single", map[string]int{"2026-01": 3}, "2026-01", true},
+		{"largest wins", map[string]int{"2026-01": 3, "2026-02": 9, "2026-03": 4}, "2026-02", true},
+		{"tie takes lowest key", map[string]int{"2026-05": 7, "2026-02": 7, "2026-09": 7}, "2026-02", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Map order is random; repeat so a tie-break bug can't pass by luck.
+			for range 20 {
+				got, ok := maxKey(tt.values)
+				if got != tt.want || ok != tt.wantOK {
+					t.Fatalf("maxKey() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+				}
+			}
+		})
+	}
+
+	if got, _ := maxKey(map[time.Weekday]int{time.Friday: 2, time.Monday: 2}); got != time.Monday {
+		t.Errorf("weekday tie = %v, want Monday", got)
+	}
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"UTC", false},
+		{"Asia/Kolkata", false},
+		{"Local", true},
+		{"Mars/Olympus_Mons", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadTimeZone(tt.name); (err != nil) != tt.wantErr {
+				t.Errorf("loadTimeZone(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateListeningSession(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	start := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		start   time.Time
+		end     time.Time
+		seconds int
+		want    string
+	}{
+		{"valid", start, now, 3600, ""},
+		{"sped up", start, now, 3 * 3600, ""},
+		{"within clock tolerance", start, now.Add(time.Minute), 60, ""},
+		{"ends before it starts", now, start, 60, "Session must end after it starts"},
+		{"zero length", now, now, 60, "Session must end after it starts"},
+		{"ends in the future", start, now.Add(time.Hour), 60, "Session ends in the future"},
+		{"nothing listened", start, now, 0, "Seconds listened does not fit the session"},
+		{"more than the playback speed allows", start, now, 3*3600 + 2, "Seconds listened does not fit the session"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateListeningSession(tt.start, tt.end, tt.seconds, now); got != tt.want {
+				t.Errorf("validateListeningSession() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// preferredLanguages returns the caller's locales in order of preference.
+// A ?lang= query parameter takes precedence over the Accept-Language header.
+func preferredLanguages(c *gin.Context) []language.Tag {
+	header := c.GetHeader("Accept-Language")
+	if lang := c.Query("lang"); lang != "" {
+		header = lang
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+	return tags
+}
+
+// localizeAudiobook replaces the catalog text with the best matching translation.
+//
+// Fallback order: the exact requested locale, then a related one (e.g. "hi" for "hi-IN"),
+// then the audiobook's own text. Fields missing from the chosen translation keep the
+// audiobook's own text. The full translation map is not sent to public clients.
+func localizeAudiobook(a *models.Audiobook, prefs []language.Tag) {
+	a.Locale = a.Language
+
+	if len(a.Translations) > 0 && len(prefs) > 0 {
+		keys := make([]string, 0, len(a.Translations))
+		for key := range a.Translations {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		// The first supported tag is the matcher's default: the audiobook's own text
+		supported := []language.Tag{language.Und}
+		if tag, err := language.Parse(a.Language); err == nil {
+			supported[0] = tag
+		}
+		for _, key := range keys {
+			supported = append(supported, language.Make(key))
+		}
+
+		_, index, confidence := language.NewMatcher(supported).Match(prefs...)
+		if confidence != language.No && index > 0 {
+			key := keys[index-1]
+			t := a.Translations[key]
+			if t.Name != "" {
+				a.Name = t.Name
+			}
+			if t.Description != "" {
+				a.Description = t.Description
+			}
+			if t.Content != "" {
+				a.Content = t.Content
+			}
+			a.Locale = key
+		}
+	}
+
+	a.Translations = nil
+}
+
+// canonicalLocale validates a locale and returns its canonical BCP 47 form
+func canonicalLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", fmt.Errorf("invalid locale %q", locale)
+	}
+	return tag.String(), nil
+}
+
+// translationsFromRequest validates the locales of a translation map
+func translationsFromRequest(req map[string]request.TranslationRequest) (map[string]models.AudiobookTranslation, error) {
+	translations := make(map[string]models.AudiobookTranslation, len(req))
+	for locale, t := range req {
+		key, err := canonicalLocale(locale)
+		if err != nil {
+			return nil, err
+		}
+		translations[key] = models.AudiobookTranslation{
+			Name:        t.Name,
+			Description: t.Description,
+			Content:     t.Content,
+		}
+	}
+	return translations, nil
+}
@@ -0,0 +1,455 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+	"live_stream/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxPlaylistItems       = 500
+	maxPlaylistTitleLength = 200
+	shareTokenBytes        = 16
+)
+
+type PlaylistController struct {
+	PlaylistCol  *mongo.Collection
+	QueueCol     *mongo.Collection
+	AudiobookCol *mongo.Collection
+	UserCol      *mongo.Collection
+}
+
+// playlistView is a playlist together with the audiobooks its items refer to
+type playlistView struct {
+	models.Playlist
+	Audiobooks map[primitive.ObjectID]models.Audiobook `json:"audiobooks"`
+}
+
+// GetPlaylists - authenticated endpoint listing the playlists the user owns or collaborates on
+func (pc *PlaylistController) GetPlaylists(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	cursor, err := pc.PlaylistCol.Find(context.TODO(),
+		bson.M{"$or": bson.A{bson.M{"ownerId": uid}, bson.M{"collaborators": uid}}},
+		options.Find().SetSort(bson.M{"updatedAt": -1}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playlists"})
+		return
+	}
+	playlists := []models.Playlist{}
+	cursor.All(context.TODO(), &playlists)
+	c.JSON(http.StatusOK, playlists)
+}
+
+// CreatePlaylist - authenticated endpoint to create an empty playlist
+func (pc *PlaylistController) CreatePlaylist(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	var req request.CreatePlaylistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len(title) > maxPlaylistTitleLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid title"})
+		return
+	}
+	if req.Visibility == "" {
+		req.Visibility = models.PlaylistPrivate
+	}
+	if req.Visibility != models.PlaylistPrivate && req.Visibility != models.PlaylistPublic {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Visibility must be private or public"})
+		return
+	}
+
+	now := time.Now()
+	playlist := models.Playlist{
+		OwnerID:       uid,
+		Title:         title,
+		Description:   strings.TrimSpace(req.Description),
+		Visibility:    req.Visibility,
+		Collaborators: []primitive.ObjectID{},
+		Items:         []models.PlaylistItem{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if playlist.Visibility == models.PlaylistPublic {
+		token, err := utils.RandomToken(shareTokenBytes)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create playlist"})
+			return
+		}
+		playlist.ShareToken = token
+	}
+
+	result, err := pc.PlaylistCol.InsertOne(context.TODO(), playlist)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create playlist"})
+		return
+	}
+	playlist.ID = result.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusOK, playlist)
+}
+
+// GetPlaylist - authenticated endpoint returning a playlist the user owns or collaborates on
+func (pc *PlaylistController) GetPlaylist(c *gin.Context) {
+	playlist, ok := pc.loadPlaylist(c, false)
+	if !ok {
+		return
+	}
+	pc.respondWithPlaylist(c, playlist)
+}
+
+// GetSharedPlaylist - public endpoint returning a public playlist by its share token
+func (pc *PlaylistController) GetSharedPlaylist(c *gin.Context) {
+	var playlist models.Playlist
+	err := pc.PlaylistCol.FindOne(context.TODO(), bson.M{
+		"shareToken": c.Param("token"),
+		"visibility": models.PlaylistPublic,
+	}).Decode(&playlist)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+	pc.respondWithPlaylist(c, playlist)
+}
+
+// UpdatePlaylist - owner endpoint to change a playlist's title, description or visibility.
+// Making a playlist public creates its share link.
+func (pc *PlaylistController) UpdatePlaylist(c *gin.Context) {
+	playlist, ok := pc.loadPlaylist(c, true)
+	if !ok {
+		return
+	}
+
+	var req request.UpdatePlaylistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := bson.M{}
+	if title := strings.TrimSpace(req.Title); title != "" {
+		if len(title) > maxPlaylistTitleLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid title"})
+			return
+		}
+		update["title"] = title
+	}
+	if req.Description != nil {
+		update["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.Visibility != "" {
+		if req.Visibility != models.PlaylistPrivate && req.Visibility != models.PlaylistPublic {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Visibility must be private or public"})
+			return
+		}
+		update["visibility"] = req.Visibility
+		if req.Visibility == models.PlaylistPublic && playlist.ShareToken == "" {
+			token, err := utils.RandomToken(shareTokenBytes)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update playlist"})
+				return
+			}
+			update["shareToken"] = token
+		}
+	}
+
+	change := bson.M{"$set": update}
+	if req.Visibility == models.PlaylistPrivate {
+		// Going private revokes the share link for good; going public again makes a new one
+		change["$unset"] = bson.M{"shareToken": ""}
+	}
+	pc.savePlaylistChange(c, playlist, change)
+}
+
+// RegenerateShareLink - owner endpoint to replace a public playlist's share link, revoking the old one
+func (pc *PlaylistController) RegenerateShareLink(c *gin.Context) {
+	playlist, ok := pc.loadPlaylist(c, true)
+	if !ok {
+		return
+	}
+	if playlist.Visibility != models.PlaylistPublic {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only public playlists can be shared"})
+		return
+	}
+
+	token, err := utils.RandomToken(shareTokenBytes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share link"})
+		return
+	}
+	pc.savePlaylistChange(c, playlist, bson.M{"$set": bson.M{"shareToken": token}})
+}
+
+// DeletePlaylist - owner endpoint to delete a playlist
+func (pc *PlaylistController) DeletePlaylist(c *gin.Context) {
+	playlist, ok := pc.loadPlaylist(c, true)
+	if !ok {
+		return
+	}
+
+	if _, err := pc.PlaylistCol.DeleteOne(context.TODO(), bson.M{"_id": playlist.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete playlist"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Playlist deleted"})
+}
+
+// AddCollaborator - owner endpoint to let another user edit the playlist's items
+func (pc *PlaylistController) AddCollaborator(c *gin.Context) {
+	playlist, ok := pc.loadPlaylist(c, true)
+	if !ok {
+		return
+	}
+
+	var req request.CollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.UserID == playlist.OwnerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The owner is already an editor"})
+		return
+	}
+	count, err := pc.UserCol.CountDocuments(context.TODO(), bson.M{"_id": req.UserID})
+	if err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	pc.savePlaylistChange(c, playlist, bson.M{"$addToSet": bson.M{"collaborators": req.UserID}})
+}
+
+// RemoveCollaborator - owner endpoint to revoke a collaborator (collaborators may also remove themselves)
+func (pc *PlaylistController) RemoveCollaborator(c *gin.Context) {
+	collaboratorID, err := primitive.ObjectIDFromHex(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	playlist, ok := pc.loadPlaylist(c, uid != collaboratorID)
+	if !ok {
+		return
+	}
+	pc.savePlaylistChange(c, playlist, bson.M{"$pull": bson.M{"collaborators": collaboratorID}})
+}
+
+// AddPlaylistItem - owner/collaborator endpoint to add an audiobook or chapter to a playlist
+func (pc *PlaylistController) AddPlaylistItem(c *gin.Context) {
+	playlist, ok := pc.loadPlaylist(c, false)
+	if !ok {
+		return
+	}
+
+	var req request.AddPlaylistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(playlist.Items) >= maxPlaylistItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Playlist is full"})
+		return
+	}
+	item, ok := pc.newItem(c, req.PlaylistItemRequest)
+	if !ok {
+		return
+	}
+
+	push := bson.M{"$each": bson.A{item}}
+	if req.Position != nil && *req.Position >= 0 {
+		push["$position"] = *req.Position
+	}
+	pc.savePlaylistChange(c, playlist, bson.M{"$push": bson.M{"items": push}})
+}
+
+// RemovePlaylistItem - owner/collaborator endpoint to remove an item from a playlist
+func (pc *PlaylistController) RemovePlaylistItem(c *gin.Context) {
+	playlist, ok := pc.loadPlaylist(c, false)
+	if !ok {
+		return
+	}
+	itemID, err := primitive.ObjectIDFromHex(c.Param("itemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	pc.savePlaylistChange(c, playlist, bson.M{"$pull": bson.M{"items": bson.M{"_id": itemID}}})
+}
+
+// ReorderPlaylistItems - owner/collaborator endpoint to reorder a playlist. The request must list
+// every item and carry the version it was based on; a stale version is rejected with 409.
+func (pc *PlaylistController) ReorderPlaylistItems(c *gin.Context) {
+	playlist, ok := pc.loadPlaylist(c, false)
+	if !ok {
+		return
+	}
+
+	var req request.ReorderItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Version != playlist.Version {
+		c.JSON(http.StatusConflict, gin.H{"error": "Playlist was changed by someone else", "playlist": playlist})
+		return
+	}
+	items, ok := reorderItems(playlist.Items, req.ItemIDs)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Item IDs must list every item exactly once"})
+		return
+	}
+
+	pc.savePlaylistChange(c, playlist, bson.M{"$set": bson.M{"items": items}})
+}
+
+// loadPlaylist fetches the :id playlist if the user may edit it: its owner, or when ownerOnly
+// is false also a collaborator. It writes the error response itself.
+func (pc *PlaylistController) loadPlaylist(c *gin.Context, ownerOnly bool) (models.Playlist, bool) {
+	var playlist models.Playlist
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return playlist, false
+	}
+
+	if err := pc.PlaylistCol.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&playlist); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return playlist, false
+	}
+
+	if playlist.OwnerID == uid {
+		return playlist, true
+	}
+	for _, collaborator := range playlist.Collaborators {
+		if collaborator == uid {
+			if ownerOnly {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can do this"})
+				return playlist, false
+			}
+			return playlist, true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+	return playlist, false
+}
+
+// savePlaylistChange applies update only if the playlist is still at the version that was loaded,
+// bumps the version and responds with the updated playlist (409 if someone else got there first)
+func (pc *PlaylistController) savePlaylistChange(c *gin.Context, playlist models.Playlist, update bson.M) {
+	update["$inc"] = bson.M{"version": 1}
+	set, _ := update["$set"].(bson.M)
+	if set == nil {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updatedAt"] = time.Now()
+
+	var updated models.Playlist
+	err := pc.PlaylistCol.FindOneAndUpdate(context.TODO(),
+		bson.M{"_id": playlist.ID, "version": playlist.Version},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusConflict, gin.H{"error": "Playlist was changed by someone else, please retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update playlist"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// respondWithPlaylist sends a playlist with its (published) audiobooks attached
+func (pc *PlaylistController) respondWithPlaylist(c *gin.Context, playlist models.Playlist) {
+	ids := make([]primitive.ObjectID, 0, len(playlist.Items))
+	for _, item := range playlist.Items {
+		ids = append(ids, item.AudiobookID)
+	}
+
+	view := playlistView{Playlist: playlist, Audiobooks: map[primitive.ObjectID]models.Audiobook{}}
+	if len(ids) > 0 {
+		filter := publishedFilter(time.Now())
+		filter["_id"] = bson.M{"$in": ids}
+		cursor, err := pc.AudiobookCol.Find(context.TODO(), filter, options.Find().SetProjection(bson.M{"audioData": 0}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audiobooks"})
+			return
+		}
+		var audiobooks []models.Audiobook
+		cursor.All(context.TODO(), &audiobooks)
+		prefs := preferredLanguages(c)
+		for _, a := range audiobooks {
+			localizeAudiobook(&a, prefs)
+			view.Audiobooks[a.ID] = a
+		}
+	}
+
+	c.Header("Vary", "Accept-Language")
+	c.JSON(http.StatusOK, view)
+}
+
+// newItem validates a requested item and gives it an ID. It writes the error response itself.
+func (pc *PlaylistController) newItem(c *gin.Context, req request.PlaylistItemRequest) (models.PlaylistItem, bool) {
+	if req.Chapter < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid chapter"})
+		return models.PlaylistItem{}, false
+	}
+
+	filter := publishedFilter(time.Now())
+	filter["_id"] = req.AudiobookID
+	count, err := pc.AudiobookCol.CountDocuments(context.TODO(), filter)
+	if err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return models.PlaylistItem{}, false
+	}
+
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	return models.PlaylistItem{
+		ID:          primitive.NewObjectID(),
+		AudiobookID: req.AudiobookID,
+		Chapter:     req.Chapter,
+		AddedBy:     uid,
+		AddedAt:     time.Now(),
+	}, true
+}
+
+// reorderItems returns items in the order of ids, which must name every item exactly once
+func reorderItems(items []models.PlaylistItem, ids []primitive.ObjectID) ([]models.PlaylistItem, bool) {
+	if len(ids) != len(items) {
+		return nil, false
+	}
+	byID := make(map[primitive.ObjectID]models.PlaylistItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	reordered := make([]models.PlaylistItem, 0, len(items))
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok {
+			return nil, false
+		}
+		delete(byID, id)
+		reordered = append(reordered, item)
+	}
+	return reordered, true
+}
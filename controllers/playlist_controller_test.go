@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	models "live_stream/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReorderItems(t *testing.T) {
+	a, b, c := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	items := []models.PlaylistItem{{ID: a}, {ID: b}, {ID: c}}
+
+	tests := []struct {
+		name   string
+		ids    []primitive.ObjectID
+		want   []primitive.ObjectID
+		wantOK bool
+	}{
+		{"same order", []primitive.ObjectID{a, b, c}, []primitive.ObjectID{a, b, c}, true},
+		{"reversed", []primitive.ObjectID{c, b, a}, []primitive.ObjectID{c, b, a}, true},
+		{"missing item", []primitive.ObjectID{a, b}, nil, false},
+		{"extra item", []primitive.ObjectID{a, b, c, primitive.NewObjectID()}, nil, false},
+		{"unknown item", []primitive.ObjectID{a, b, primitive.NewObjectID()}, nil, false},
+		{"duplicate item", []primitive.ObjectID{a, a, b}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := reorderItems(items, tt.ids)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			var gotIDs []primitive.ObjectID
+			for _, item := range got {
+				gotIDs = append(gotIDs, item.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.want) {
+				t.Errorf("reorderItems() = %v, want %v", gotIDs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxProgressUpdatesPerBatch = 100
+	maxContinueListening       = 50
+	defaultPlaybackSpeed       = 1.0
+	finishedFraction           = 0.95
+)
+
+var errInvalidProgress = errors.New("invalid progress")
+
+// progressSyncResult tells the device whether its update won, and the position every device should now use
+type progressSyncResult struct {
+	AudiobookID primitive.ObjectID       `json:"audiobookId"`
+	Applied     bool                     `json:"applied"`
+	Progress    *models.PlaybackProgress `json:"progress,omitempty"`
+	Error       string                   `json:"error,omitempty"`
+}
+
+// SyncProgress - authenticated endpoint for players to upload a batch of playback positions.
+// Device timestamps are shifted by the device's clock skew (server time minus sentAt), and an update
+// only replaces the stored position when it is newer (last write wins).
+func (uc *UserController) SyncProgress(c *gin.Context) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req request.SyncProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Updates) > maxProgressUpdatesPerBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many updates in one batch"})
+		return
+	}
+
+	ctx := context.TODO()
+	now := time.Now()
+	skew := now.Sub(req.SentAt)
+
+	ids := make([]primitive.ObjectID, 0, len(req.Updates))
+	for _, u := range req.Updates {
+		ids = append(ids, u.AudiobookID)
+	}
+	durations, err := uc.audiobookDurations(ctx, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audiobooks"})
+		return
+	}
+
+	results := make([]progressSyncResult, 0, len(req.Updates))
+	for _, u := range req.Updates {
+		result := progressSyncResult{AudiobookID: u.AudiobookID}
+		duration, ok := durations[u.AudiobookID]
+		if !ok {
+			result.Error = "Audiobook not found"
+			results = append(results, result)
+			continue
+		}
+		applied, err := uc.saveProgress(ctx, uid, req.DeviceID, u, reconcileClientTime(u.UpdatedAt, skew, now), duration)
+		switch {
+		case err == errInvalidProgress:
+			result.Error = "Invalid position"
+			results = append(results, result)
+			continue
+		case err != nil:
+			result.Error = "Failed to save progress"
+		}
+		result.Applied = applied
+
+		var current models.PlaybackProgress
+		if err := uc.ProgressCol.FindOne(ctx, bson.M{"userId": uid, "audiobookId": u.AudiobookID}).Decode(&current); err == nil {
+			result.Progress = &current
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results, "serverTime": now})
+}
+
+// reconcileClientTime moves a device timestamp onto the server clock using the device's skew.
+// Nothing can have happened in the future, whatever the device clock says.
+func reconcileClientTime(t time.Time, skew time.Duration, now time.Time) time.Time {
+	t = t.Add(skew)
+	if t.After(now) {
+		return now
+	}
+	return t
+}
+
+// saveProgress stores a position reached at updatedAt (server clock) unless a newer one is
+// already stored, in which case it reports false. duration, when known, marks the audiobook
+// finished once the last few percent are reached.
+func (uc *UserController) saveProgress(ctx context.Context, uid primitive.ObjectID, deviceID string, u request.ProgressUpdate, updatedAt time.Time, duration int) (bool, error) {
+	if u.PositionSeconds < 0 || u.Chapter < 0 || u.PlaybackSpeed < 0 {
+		return false, errInvalidProgress
+	}
+	if u.PlaybackSpeed == 0 {
+		u.PlaybackSpeed = defaultPlaybackSpeed
+	}
+	if duration > 0 && u.PositionSeconds >= finishedFraction*float64(duration) {
+		u.Completed = true
+	}
+
+	newer := bson.M{"userId": uid, "audiobookId": u.AudiobookID, "updatedAt": bson.M{"$lt": updatedAt}}
+	_, err := uc.ProgressCol.UpdateOne(ctx, newer,
+		bson.M{"$set": bson.M{
+			"positionSeconds": u.PositionSeconds,
+			"chapter":         u.Chapter,
+			"playbackSpeed":   u.PlaybackSpeed,
+			"completed":       u.Completed,
+			"deviceId":        deviceID,
+			"updatedAt":       updatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// A newer position is already stored: the upsert collided with it
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// The position is stored either way; shelves catch up on the next update
+	if err := uc.applyProgressShelves(ctx, uid, u.AudiobookID, u.Completed); err != nil {
+		log.Printf("Failed to update shelves of user %s for audiobook %s: %v", uid.Hex(), u.AudiobookID.Hex(), err)
+	}
+	return true, nil
+}
+
+// audiobookDurations looks up the running time of the given audiobooks.
+// Audiobooks that don't exist or are in the trash are missing from the map.
+func (uc *UserController) audiobookDurations(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	durations := map[primitive.ObjectID]int{}
+	cursor, err := uc.StreamCol.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deletedAt": nil}, options.Find().SetProjection(bson.M{"durationSeconds": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var audiobooks []models.Audiobook
+	if err := cursor.All(ctx, &audiobooks); err != nil {
+		return nil, err
+	}
+	for _, a := range audiobooks {
+		durations[a.ID] = a.DurationSeconds
+	}
+	return durations, nil
+}
+
+// GetProgress - authenticated endpoint returning the user's playback positions,
+// optionally limited to one audiobook with ?audiobookId=
+func (uc *UserController) GetProgress(c *gin.Context) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	filter := bson.M{"userId": uid}
+	if id := c.Query("audiobookId"); id != "" {
+		audiobookID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+			return
+		}
+		filter["audiobookId"] = audiobookID
+	}
+
+	cursor, err := uc.ProgressCol.Find(context.TODO(), filter, options.Find().SetSort(bson.M{"updatedAt": -1}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch progress"})
+		return
+	}
+	progress := []models.PlaybackProgress{}
+	cursor.All(context.TODO(), &progress)
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetContinueListening - authenticated endpoint listing unfinished audiobooks, most recently played first
+func (uc *UserController) GetContinueListening(c *gin.Context) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > maxContinueListening {
+		limit = 20
+	}
+
+	ctx := context.TODO()
+	cursor, err := uc.ProgressCol.Find(ctx,
+		bson.M{"userId": uid, "completed": false},
+		options.Find().SetSort(bson.M{"updatedAt": -1}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch progress"})
+		return
+	}
+	var progress []models.PlaybackProgress
+	cursor.All(ctx, &progress)
+
+	ids := make([]primitive.ObjectID, 0, len(progress))
+	for _, p := range progress {
+		ids = append(ids, p.AudiobookID)
+	}
+
+	// Titles that were unpublished or trashed since drop out of the list
+	byID := map[primitive.ObjectID]models.Audiobook{}
+	if len(ids) > 0 {
+		filter := publishedFilter(time.Now())
+		filter["_id"] = bson.M{"$in": ids}
+		cursor, err := uc.StreamCol.Find(ctx, filter, options.Find().SetProjection(bson.M{"audioData": 0}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audiobooks"})
+			return
+		}
+		var audiobooks []models.Audiobook
+		cursor.All(ctx, &audiobooks)
+		for _, a := range audiobooks {
+			byID[a.ID] = a
+		}
+	}
+
+	type continueListeningItem struct {
+		Audiobook models.Audiobook        `json:"audiobook"`
+		Progress  models.PlaybackProgress `json:"progress"`
+	}
+	items := []continueListeningItem{}
+	prefs := preferredLanguages(c)
+	for _, p := range progress {
+		if a, ok := byID[p.AudiobookID]; ok {
+			localizeAudiobook(&a, prefs)
+			items = append(items, continueListeningItem{Audiobook: a, Progress: p})
+		}
+	}
+
+	c.Header("Vary", "Accept-Language")
+	c.JSON(http.StatusOK, items)
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconcileClientTime(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		device time.Time
+		skew   time.Duration
+		want   time.Time
+	}{
+		{"clocks agree", now.Add(-time.Minute), 0, now.Add(-time.Minute)},
+		{"device clock behind", now.Add(-time.Hour), 50 * time.Minute, now.Add(-10 * time.Minute)},
+		{"device clock ahead", now.Add(time.Hour), -70 * time.Minute, now.Add(-10 * time.Minute)},
+		{"still in the future after correction", now.Add(time.Hour), 0, now},
+		{"exactly now", now, 0, now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reconcileClientTime(tt.device, tt.skew, now); !got.Equal(tt.want) {
+				t.Errorf("reconcileClientTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
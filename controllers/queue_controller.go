@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxQueueItems = 200
+
+// GetQueue - authenticated endpoint returning the user's "up next" queue
+func (pc *PlaylistController) GetQueue(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	c.JSON(http.StatusOK, pc.loadQueue(uid))
+}
+
+// Enqueue - authenticated endpoint to add an audiobook or chapter to the queue,
+// either to play next or at the end
+func (pc *PlaylistController) Enqueue(c *gin.Context) {
+	var req request.EnqueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, ok := pc.newItem(c, req.PlaylistItemRequest)
+	if !ok {
+		return
+	}
+
+	push := bson.M{"$each": bson.A{item}}
+	if req.PlayNext {
+		push["$position"] = 0
+	}
+	pc.updateQueueIf(c, queueHasRoom(1), bson.M{"$push": bson.M{"items": push}}, true, http.StatusBadRequest, "Queue is full")
+}
+
+// queueHasRoom matches queues that can take n more items
+func queueHasRoom(n int) bson.M {
+	return bson.M{"items." + strconv.Itoa(maxQueueItems-n): bson.M{"$exists": false}}
+}
+
+// EnqueuePlaylist - authenticated endpoint to append every item of a playlist to the queue.
+// Owners and collaborators can queue their playlists; anyone else needs the share link's ?token=.
+func (pc *PlaylistController) EnqueuePlaylist(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return
+	}
+
+	access := bson.A{
+		bson.M{"ownerId": uid},
+		bson.M{"collaborators": uid},
+	}
+	if token := c.Query("token"); token != "" {
+		access = append(access, bson.M{"visibility": models.PlaylistPublic, "shareToken": token})
+	}
+
+	var playlist models.Playlist
+	err = pc.PlaylistCol.FindOne(context.TODO(), bson.M{"_id": objID, "$or": access}).Decode(&playlist)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+
+	items := bson.A{}
+	now := time.Now()
+	for _, item := range playlist.Items {
+		items = append(items, models.PlaylistItem{
+			ID:          primitive.NewObjectID(),
+			AudiobookID: item.AudiobookID,
+			Chapter:     item.Chapter,
+			AddedBy:     uid,
+			AddedAt:     now,
+		})
+	}
+	if len(items) > maxQueueItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Queue is full"})
+		return
+	}
+	pc.updateQueueIf(c, queueHasRoom(len(items)), bson.M{"$push": bson.M{"items": bson.M{"$each": items}}}, true, http.StatusBadRequest, "Queue is full")
+}
+
+// RemoveFromQueue - authenticated endpoint to remove an item from the queue
+func (pc *PlaylistController) RemoveFromQueue(c *gin.Context) {
+	itemID, err := primitive.ObjectIDFromHex(c.Param("itemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+	pc.updateQueue(c, bson.M{"$pull": bson.M{"items": bson.M{"_id": itemID}}})
+}
+
+// ReorderQueue - authenticated endpoint to reorder the queue. The request must list every item and
+// carry the queue version it was based on, so a device with a stale queue gets 409 instead of
+// overwriting changes made on another device.
+func (pc *PlaylistController) ReorderQueue(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	var req request.ReorderItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	queue := pc.loadQueue(uid)
+	if req.Version != queue.Version {
+		c.JSON(http.StatusConflict, gin.H{"error": "Queue was changed on another device", "queue": queue})
+		return
+	}
+	items, ok := reorderItems(queue.Items, req.ItemIDs)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Item IDs must list every item exactly once"})
+		return
+	}
+
+	var updated models.ListeningQueue
+	err := pc.QueueCol.FindOneAndUpdate(context.TODO(),
+		bson.M{"_id": uid, "version": queue.Version},
+		bson.M{"$set": bson.M{"items": items, "updatedAt": time.Now()}, "$inc": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusConflict, gin.H{"error": "Queue was changed on another device, please retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update queue"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// AdvanceQueue - authenticated endpoint the player calls when an item finishes: it drops the
+// finished item if it is still first and returns the queue, whose first item is what plays next.
+// Another device that already advanced past the item gets 409 with the current queue.
+func (pc *PlaylistController) AdvanceQueue(c *gin.Context) {
+	var req request.AdvanceQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	pc.updateQueueIf(c, bson.M{"items.0._id": req.ItemID}, bson.M{"$pop": bson.M{"items": -1}}, false, http.StatusConflict, "Queue already moved past this item")
+}
+
+// ClearQueue - authenticated endpoint to empty the queue
+func (pc *PlaylistController) ClearQueue(c *gin.Context) {
+	pc.updateQueue(c, bson.M{"$set": bson.M{"items": []models.PlaylistItem{}}})
+}
+
+// loadQueue returns the user's queue, or an empty one if they never queued anything
+func (pc *PlaylistController) loadQueue(uid primitive.ObjectID) models.ListeningQueue {
+	queue := models.ListeningQueue{UserID: uid, Items: []models.PlaylistItem{}}
+	pc.QueueCol.FindOne(context.TODO(), bson.M{"_id": uid}).Decode(&queue)
+	return queue
+}
+
+// updateQueue applies update to the user's queue (creating it if needed), bumps its version
+// and responds with the result
+func (pc *PlaylistController) updateQueue(c *gin.Context, update bson.M) {
+	pc.updateQueueIf(c, nil, update, true, 0, "")
+}
+
+// updateQueueIf is updateQueue for changes that only apply while the queue matches cond.
+// Otherwise it responds with status and message, along with the current queue.
+func (pc *PlaylistController) updateQueueIf(c *gin.Context, cond bson.M, update bson.M, upsert bool, status int, message string) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	update["$inc"] = bson.M{"version": 1}
+	set, _ := update["$set"].(bson.M)
+	if set == nil {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updatedAt"] = time.Now()
+
+	filter := bson.M{"_id": uid}
+	for k, v := range cond {
+		filter[k] = v
+	}
+
+	var queue models.ListeningQueue
+	err := pc.QueueCol.FindOneAndUpdate(context.TODO(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetUpsert(upsert).SetReturnDocument(options.After),
+	).Decode(&queue)
+	// With upsert, a queue that exists but doesn't match cond collides on _id
+	if err == mongo.ErrNoDocuments || mongo.IsDuplicateKeyError(err) {
+		c.JSON(status, gin.H{"error": message, "queue": pc.loadQueue(uid)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update queue"})
+		return
+	}
+	if queue.Items == nil {
+		queue.Items = []models.PlaylistItem{}
+	}
+	c.JSON(http.StatusOK, queue)
+}
@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestQueueHasRoom(t *testing.T) {
+	tests := []struct {
+		n    int
+		want bson.M
+	}{
+		{1, bson.M{"items.199": bson.M{"$exists": false}}},
+		{10, bson.M{"items.190": bson.M{"$exists": false}}},
+		{maxQueueItems, bson.M{"items.0": bson.M{"$exists": false}}},
+	}
+	for _, tt := range tests {
+		if got := queueHasRoom(tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("queueHasRoom(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxReviewTitleLength = 200
+	maxReviewBodyLength  = 5000
+	maxReviewsPerPage    = 50
+)
+
+// Sort orders accepted by GetReviews ?sort=
+var reviewSorts = map[string]bson.D{
+	"helpful": {{Key: "helpfulCount", Value: -1}, {Key: "createdAt", Value: -1}},
+	"recent":  {{Key: "createdAt", Value: -1}},
+	"highest": {{Key: "rating", Value: -1}, {Key: "createdAt", Value: -1}},
+	"lowest":  {{Key: "rating", Value: 1}, {Key: "createdAt", Value: -1}},
+}
+
+type ReviewController struct {
+	ReviewCol     *mongo.Collection
+	ReviewVoteCol *mongo.Collection
+	AudiobookCol  *mongo.Collection
+	UserCol       *mongo.Collection
+	SlugCol       *mongo.Collection
+}
+
+// GetReviews - public endpoint listing the visible reviews of an audiobook (by ID or slug).
+// ?sort= is helpful (default), recent, highest or lowest; paginated with ?page= and ?limit=.
+func (rc *ReviewController) GetReviews(c *gin.Context) {
+	objID, ok := resolveAudiobookParam(c, rc.SlugCol)
+	if !ok {
+		return
+	}
+
+	sort, ok := reviewSorts[c.DefaultQuery("sort", "helpful")]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sort must be helpful, recent, highest or lowest"})
+		return
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > maxReviewsPerPage {
+		limit = 20
+	}
+
+	ctx := context.TODO()
+	filter := bson.M{"audiobookId": objID, "status": models.ReviewStatusVisible}
+	if c.Query("withText") == "true" {
+		filter["body"] = bson.M{"$nin": bson.A{nil, ""}}
+	}
+
+	total, err := rc.ReviewCol.CountDocuments(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+		return
+	}
+
+	cursor, err := rc.ReviewCol.Find(ctx, filter,
+		options.Find().SetSort(sort).SetSkip(int64((page-1)*limit)).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+		return
+	}
+	reviews := []models.Review{}
+	cursor.All(ctx, &reviews)
+
+	var audiobook models.Audiobook
+	rc.AudiobookCol.FindOne(ctx, bson.M{"_id": objID},
+		options.FindOne().SetProjection(bson.M{"ratingAverage": 1, "ratingCount": 1, "ratingDistribution": 1}),
+	).Decode(&audiobook)
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews":            reviews,
+		"total":              total,
+		"page":               page,
+		"ratingAverage":      audiobook.RatingAverage,
+		"ratingCount":        audiobook.RatingCount,
+		"ratingDistribution": audiobook.RatingDistribution,
+	})
+}
+
+// GetMyReview - authenticated endpoint returning the user's own review of an audiobook
+func (rc *ReviewController) GetMyReview(c *gin.Context) {
+	objID, ok := resolveAudiobookParam(c, rc.SlugCol)
+	if !ok {
+		return
+	}
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	var review models.Review
+	if err := rc.ReviewCol.FindOne(context.TODO(), bson.M{"audiobookId": objID, "userId": uid}).Decode(&review); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+// SaveReview - authenticated endpoint to rate a published audiobook 1-5 stars with an optional
+// written review. Saving again edits the user's existing review.
+func (rc *ReviewController) SaveReview(c *gin.Context) {
+	objID, ok := resolveAudiobookParam(c, rc.SlugCol)
+	if !ok {
+		return
+	}
+
+	var req request.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	req.Body = strings.TrimSpace(req.Body)
+	if len(req.Title) > maxReviewTitleLength || len(req.Body) > maxReviewBodyLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Review is too long"})
+		return
+	}
+
+	ctx := context.TODO()
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	published := publishedFilter(time.Now())
+	published["_id"] = objID
+	if count, err := rc.AudiobookCol.CountDocuments(ctx, published); err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return
+	}
+
+	var user models.User
+	if err := rc.UserCol.FindOne(ctx, bson.M{"_id": uid}).Decode(&user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user info"})
+		return
+	}
+
+	now := time.Now()
+	var review models.Review
+	save := func() error {
+		return rc.ReviewCol.FindOneAndUpdate(ctx,
+			bson.M{"audiobookId": objID, "userId": uid},
+			bson.M{
+				"$set": bson.M{
+					"userName":  user.FullName,
+					"rating":    req.Rating,
+					"title":     req.Title,
+					"body":      req.Body,
+					"updatedAt": now,
+				},
+				// Edits keep a moderator's decision
+				"$setOnInsert": bson.M{
+					"helpfulCount": 0,
+					"status":       models.ReviewStatusVisible,
+					"createdAt":    now,
+				},
+			},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&review)
+	}
+	err := save()
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent first save inserted the review: this one becomes an edit of it
+		err = save()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save review"})
+		return
+	}
+
+	rc.refreshRatingAggregates(ctx, objID)
+	c.JSON(http.StatusOK, review)
+}
+
+// DeleteMyReview - authenticated endpoint to remove the user's own review of an audiobook
+func (rc *ReviewController) DeleteMyReview(c *gin.Context) {
+	objID, ok := resolveAudiobookParam(c, rc.SlugCol)
+	if !ok {
+		return
+	}
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	ctx := context.TODO()
+	var review models.Review
+	if err := rc.ReviewCol.FindOneAndDelete(ctx, bson.M{"audiobookId": objID, "userId": uid}).Decode(&review); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		return
+	}
+	rc.ReviewVoteCol.DeleteMany(ctx, bson.M{"reviewId": review.ID})
+
+	rc.refreshRatingAggregates(ctx, objID)
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted"})
+}
+
+// ToggleHelpfulVote - authenticated endpoint to mark (or unmark) a review as helpful
+func (rc *ReviewController) ToggleHelpfulVote(c *gin.Context) {
+	reviewID, err := primitive.ObjectIDFromHex(c.Param("reviewId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	ctx := context.TODO()
+	var review models.Review
+	err = rc.ReviewCol.FindOne(ctx, bson.M{"_id": reviewID, "status": models.ReviewStatusVisible}).Decode(&review)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		return
+	}
+	if review.UserID == uid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot vote on your own review"})
+		return
+	}
+
+	var message string
+	err = withTransaction(ctx, rc.ReviewCol.Database().Client(), func(ctx context.Context) error {
+		key := bson.M{"reviewId": reviewID, "userId": uid}
+		delta := 1
+		message = "Marked as helpful"
+
+		result, err := rc.ReviewVoteCol.DeleteOne(ctx, key)
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount > 0 {
+			delta = -1
+			message = "Helpful vote removed"
+		} else if _, err := rc.ReviewVoteCol.InsertOne(ctx, models.ReviewVote{ReviewID: reviewID, UserID: uid, CreatedAt: time.Now()}); err != nil {
+			return err
+		}
+
+		_, err = rc.ReviewCol.UpdateOne(ctx, bson.M{"_id": reviewID}, bson.M{"$inc": bson.M{"helpfulCount": delta}})
+		return err
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Vote was changed concurrently, please retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vote"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// GetAllReviews - admin endpoint listing reviews for moderation, newest first.
+// Optional ?status= and ?audiobookId= filters.
+func (rc *ReviewController) GetAllReviews(c *gin.Context) {
+	filter := bson.M{}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if id := c.Query("audiobookId"); id != "" {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+			return
+		}
+		filter["audiobookId"] = objID
+	}
+
+	cursor, err := rc.ReviewCol.Find(context.TODO(), filter, options.Find().SetSort(bson.M{"createdAt": -1}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+		return
+	}
+	reviews := []models.Review{}
+	cursor.All(context.TODO(), &reviews)
+	c.JSON(http.StatusOK, reviews)
+}
+
+// ModerateReview - admin endpoint to hide a review (or show it again), with an optional note
+func (rc *ReviewController) ModerateReview(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var req request.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Status != models.ReviewStatusVisible && req.Status != models.ReviewStatusHidden {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be visible or hidden"})
+		return
+	}
+
+	ctx := context.TODO()
+	now := time.Now()
+	moderatorID, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+	var review models.Review
+	err = rc.ReviewCol.FindOneAndUpdate(ctx,
+		bson.M{"_id": objID},
+		bson.M{"$set": bson.M{
+			"status":         req.Status,
+			"moderatedBy":    moderatorID,
+			"moderatedAt":    now,
+			"moderationNote": req.Note,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&review)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		return
+	}
+
+	rc.refreshRatingAggregates(ctx, review.AudiobookID)
+	c.JSON(http.StatusOK, review)
+}
+
+// DeleteReview - admin endpoint to permanently remove a review
+func (rc *ReviewController) DeleteReview(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	ctx := context.TODO()
+	var review models.Review
+	if err := rc.ReviewCol.FindOneAndDelete(ctx, bson.M{"_id": objID}).Decode(&review); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		return
+	}
+	rc.ReviewVoteCol.DeleteMany(ctx, bson.M{"reviewId": objID})
+
+	rc.refreshRatingAggregates(ctx, review.AudiobookID)
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted"})
+}
+
+// refreshRatingAggregates recomputes an audiobook's rating average and distribution from its visible reviews
+func (rc *ReviewController) refreshRatingAggregates(ctx context.Context, audiobookID primitive.ObjectID) error {
+	cursor, err := rc.ReviewCol.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"audiobookId": audiobookID, "status": models.ReviewStatusVisible}}},
+		{{Key: "$group", Value: bson.M{"_id": "$rating", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		Rating int `bson:"_id"`
+		Count  int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+
+	var distribution [5]int
+	count, sum := 0, 0
+	for _, g := range groups {
+		if g.Rating < 1 || g.Rating > 5 {
+			continue
+		}
+		distribution[g.Rating-1] = g.Count
+		count += g.Count
+		sum += g.Rating * g.Count
+	}
+	average := 0.0
+	if count > 0 {
+		average = float64(sum) / float64(count)
+	}
+
+	_, err = rc.AudiobookCol.UpdateOne(ctx, bson.M{"_id": audiobookID}, bson.M{"$set": bson.M{
+		"ratingAverage":      average,
+		"ratingCount":        count,
+		"ratingDistribution": distribution,
+	}})
+	return err
+}
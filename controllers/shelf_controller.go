@@ -0,0 +1,377 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	models "live_stream/models"
+	request "live_stream/models/requests"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxShelfNameLength = 100
+	maxShelfItems      = 500
+)
+
+// shelfSummary is one entry of the user's shelf list
+type shelfSummary struct {
+	Key     string `json:"key"` // Built-in shelf name or custom shelf ID, used in shelf URLs
+	Name    string `json:"name"`
+	BuiltIn bool   `json:"builtIn"`
+	Count   int    `json:"count"`
+}
+
+// shelfEntry is an audiobook on a shelf together with the user's progress in it
+type shelfEntry struct {
+	Audiobook       models.Audiobook         `json:"audiobook"`
+	AddedAt         time.Time                `json:"addedAt"`
+	Progress        *models.PlaybackProgress `json:"progress,omitempty"`
+	PercentComplete *float64                 `json:"percentComplete,omitempty"` // Only when the audiobook's duration is known
+}
+
+// GetShelves - authenticated endpoint listing the user's built-in and custom shelves with item counts
+func (uc *UserController) GetShelves(c *gin.Context) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ctx := context.TODO()
+	cursor, err := uc.ShelfItemCol.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": uid}}},
+		{{Key: "$group", Value: bson.M{"_id": "$shelf", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shelves"})
+		return
+	}
+	var groups []struct {
+		Shelf string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	cursor.All(ctx, &groups)
+	counts := map[string]int{}
+	for _, g := range groups {
+		counts[g.Shelf] = g.Count
+	}
+
+	shelves := []shelfSummary{}
+	for _, key := range models.BuiltInShelves {
+		shelves = append(shelves, shelfSummary{Key: key, Name: key, BuiltIn: true, Count: counts[key]})
+	}
+
+	cursor, err = uc.ShelfCol.Find(ctx, bson.M{"userId": uid}, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shelves"})
+		return
+	}
+	var custom []models.Shelf
+	cursor.All(ctx, &custom)
+	for _, s := range custom {
+		shelves = append(shelves, shelfSummary{Key: s.ID.Hex(), Name: s.Name, Count: counts[s.ID.Hex()]})
+	}
+
+	c.JSON(http.StatusOK, shelves)
+}
+
+// CreateShelf - authenticated endpoint to create a custom shelf
+func (uc *UserController) CreateShelf(c *gin.Context) {
+	uid, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req request.ShelfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxShelfNameLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shelf name"})
+		return
+	}
+
+	now := time.Now()
+	shelf := models.Shelf{UserID: uid, Name: name, CreatedAt: now, UpdatedAt: now}
+	result, err := uc.ShelfCol.InsertOne(context.TODO(), shelf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create shelf"})
+		return
+	}
+	shelf.ID = result.InsertedID.(primitive.ObjectID)
+	c.JSON(http.StatusOK, shelf)
+}
+
+// RenameShelf - authenticated endpoint to rename one of the user's custom shelves
+func (uc *UserController) RenameShelf(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	objID, err := primitive.ObjectIDFromHex(c.Param("shelf"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only custom shelves can be renamed"})
+		return
+	}
+
+	var req request.ShelfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxShelfNameLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shelf name"})
+		return
+	}
+
+	result, err := uc.ShelfCol.UpdateOne(context.TODO(),
+		bson.M{"_id": objID, "userId": uid},
+		bson.M{"$set": bson.M{"name": name, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename shelf"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shelf not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shelf renamed"})
+}
+
+// DeleteShelf - authenticated endpoint to delete a custom shelf and everything on it
+func (uc *UserController) DeleteShelf(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	objID, err := primitive.ObjectIDFromHex(c.Param("shelf"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only custom shelves can be deleted"})
+		return
+	}
+
+	ctx := context.TODO()
+	result, err := uc.ShelfCol.DeleteOne(ctx, bson.M{"_id": objID, "userId": uid})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete shelf"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shelf not found"})
+		return
+	}
+	uc.ShelfItemCol.DeleteMany(ctx, bson.M{"userId": uid, "shelf": objID.Hex()})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Shelf deleted"})
+}
+
+// GetShelf - authenticated endpoint listing the audiobooks on a shelf, most recently added first,
+// with the user's playback progress merged in
+func (uc *UserController) GetShelf(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	shelf, ok := uc.resolveShelf(c, uid)
+	if !ok {
+		return
+	}
+
+	ctx := context.TODO()
+	cursor, err := uc.ShelfItemCol.Find(ctx,
+		bson.M{"userId": uid, "shelf": shelf},
+		options.Find().SetSort(bson.M{"addedAt": -1}).SetLimit(maxShelfItems),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shelf"})
+		return
+	}
+	var items []models.ShelfItem
+	cursor.All(ctx, &items)
+
+	ids := make([]primitive.ObjectID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.AudiobookID)
+	}
+
+	audiobooks := map[primitive.ObjectID]models.Audiobook{}
+	progress := map[primitive.ObjectID]models.PlaybackProgress{}
+	if len(ids) > 0 {
+		filter := publishedFilter(time.Now())
+		filter["_id"] = bson.M{"$in": ids}
+		cursor, err := uc.StreamCol.Find(ctx, filter, options.Find().SetProjection(bson.M{"audioData": 0}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audiobooks"})
+			return
+		}
+		var found []models.Audiobook
+		cursor.All(ctx, &found)
+		for _, a := range found {
+			audiobooks[a.ID] = a
+		}
+
+		cursor, err = uc.ProgressCol.Find(ctx, bson.M{"userId": uid, "audiobookId": bson.M{"$in": ids}})
+		if err == nil {
+			var found []models.PlaybackProgress
+			cursor.All(ctx, &found)
+			for _, p := range found {
+				progress[p.AudiobookID] = p
+			}
+		}
+	}
+
+	entries := []shelfEntry{}
+	prefs := preferredLanguages(c)
+	for _, item := range items {
+		a, ok := audiobooks[item.AudiobookID]
+		if !ok {
+			continue
+		}
+		localizeAudiobook(&a, prefs)
+		entry := shelfEntry{Audiobook: a, AddedAt: item.AddedAt}
+		if p, ok := progress[item.AudiobookID]; ok {
+			entry.Progress = &p
+			if a.DurationSeconds > 0 {
+				percent := 100.0
+				if !p.Completed {
+					percent = min(100*p.PositionSeconds/float64(a.DurationSeconds), 100)
+				}
+				entry.PercentComplete = &percent
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	c.Header("Vary", "Accept-Language")
+	c.JSON(http.StatusOK, entries)
+}
+
+// AddToShelf - authenticated endpoint to put an audiobook on a shelf.
+// Putting it on the finished shelf also marks it completed.
+func (uc *UserController) AddToShelf(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	shelf, ok := uc.resolveShelf(c, uid)
+	if !ok {
+		return
+	}
+
+	var req request.ShelfItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.TODO()
+	count, err := uc.StreamCol.CountDocuments(ctx, bson.M{"_id": req.AudiobookID, "deletedAt": nil})
+	if err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook not found"})
+		return
+	}
+
+	if shelf == models.ShelfFinished {
+		_, err := uc.ProgressCol.UpdateOne(ctx,
+			bson.M{"userId": uid, "audiobookId": req.AudiobookID},
+			bson.M{"$set": bson.M{"completed": true}},
+		)
+		if err == nil {
+			err = uc.applyProgressShelves(ctx, uid, req.AudiobookID, true)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add to shelf"})
+			return
+		}
+	} else if err := uc.addToShelf(ctx, uid, shelf, req.AudiobookID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add to shelf"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Added to shelf"})
+}
+
+// RemoveFromShelf - authenticated endpoint to take an audiobook off a shelf
+func (uc *UserController) RemoveFromShelf(c *gin.Context) {
+	uid, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	shelf, ok := uc.resolveShelf(c, uid)
+	if !ok {
+		return
+	}
+	audiobookID, err := primitive.ObjectIDFromHex(c.Param("audiobookId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audiobook ID"})
+		return
+	}
+
+	result, err := uc.ShelfItemCol.DeleteOne(context.TODO(), bson.M{"userId": uid, "shelf": shelf, "audiobookId": audiobookID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove from shelf"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audiobook is not on this shelf"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Removed from shelf"})
+}
+
+// resolveShelf maps the :shelf parameter to a shelf key: a built-in shelf name, or the ID of
+// one of the user's custom shelves. It writes the error response itself.
+func (uc *UserController) resolveShelf(c *gin.Context, uid primitive.ObjectID) (string, bool) {
+	param := c.Param("shelf")
+	for _, key := range models.BuiltInShelves {
+		if param == key {
+			return key, true
+		}
+	}
+
+	objID, err := primitive.ObjectIDFromHex(param)
+	if err == nil {
+		count, err := uc.ShelfCol.CountDocuments(context.TODO(), bson.M{"_id": objID, "userId": uid})
+		if err == nil && count > 0 {
+			return objID.Hex(), true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "Shelf not found"})
+	return "", false
+}
+
+// addToShelf puts an audiobook on a shelf, keeping the original date if it is already there
+func (uc *UserController) addToShelf(ctx context.Context, uid primitive.ObjectID, shelf string, audiobookID primitive.ObjectID) error {
+	_, err := uc.ShelfItemCol.UpdateOne(ctx,
+		bson.M{"userId": uid, "shelf": shelf, "audiobookId": audiobookID},
+		bson.M{"$setOnInsert": models.ShelfItem{UserID: uid, Shelf: shelf, AudiobookID: audiobookID, AddedAt: time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// applyProgressShelves moves an audiobook between the built-in shelves as the user listens:
+// playing it takes it off the wishlist and into the library, finishing it moves it to finished.
+func (uc *UserController) applyProgressShelves(ctx context.Context, uid, audiobookID primitive.ObjectID, completed bool) error {
+	item := bson.M{"userId": uid, "audiobookId": audiobookID}
+
+	if completed {
+		if err := uc.addToShelf(ctx, uid, models.ShelfFinished, audiobookID); err != nil {
+			return err
+		}
+		item["shelf"] = bson.M{"$in": bson.A{models.ShelfLibrary, models.ShelfWishlist}}
+		_, err := uc.ShelfItemCol.DeleteMany(ctx, item)
+		return err
+	}
+
+	_, err := uc.ShelfItemCol.DeleteOne(ctx, bson.M{"userId": uid, "audiobookId": audiobookID, "shelf": models.ShelfWishlist})
+	if err != nil {
+		return err
+	}
+	finished, err := uc.ShelfItemCol.CountDocuments(ctx, bson.M{"userId": uid, "audiobookId": audiobookID, "shelf": models.ShelfFinished})
+	if err != nil || finished > 0 {
+		return err
+	}
+	return uc.addToShelf(ctx, uid, models.ShelfLibrary, audiobookID)
+}
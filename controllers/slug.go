@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	models "live_stream/models"
+	"live_stream/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// assignSlug makes sure the entity's current slug is derived from name and returns it.
+// A new unique slug is generated when the name changes; the previous one stays in the
+// slugs collection as history so old links keep resolving.
+func assignSlug(ctx context.Context, slugCol *mongo.Collection, entityType string, entityID primitive.ObjectID, name string) (string, error) {
+	base := utils.Slugify(name)
+	if base == "" {
+		base = entityType
+	}
+
+	var current models.Slug
+	err := slugCol.FindOne(ctx, bson.M{"entityType": entityType, "entityId": entityID, "isCurrent": true}).Decode(&current)
+	if err == nil && slugMatchesBase(current.Slug, base) {
+		return current.Slug, nil
+	}
+	if err != nil && err != mongo.ErrNoDocuments {
+		return "", err
+	}
+
+	var slug string
+	for i := 1; slug == ""; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = base + "-" + strconv.Itoa(i)
+		}
+
+		var existing models.Slug
+		err := slugCol.FindOne(ctx, bson.M{"entityType": entityType, "slug": candidate}).Decode(&existing)
+		switch {
+		case err == mongo.ErrNoDocuments:
+			_, err = slugCol.InsertOne(ctx, models.Slug{
+				Slug:       candidate,
+				EntityType: entityType,
+				EntityID:   entityID,
+				IsCurrent:  true,
+				CreatedAt:  time.Now(),
+			})
+			if mongo.IsDuplicateKeyError(err) {
+				continue // taken concurrently, try the next suffix
+			}
+			if err != nil {
+				return "", err
+			}
+			slug = candidate
+		case err != nil:
+			return "", err
+		case existing.EntityID == entityID:
+			// Renamed back to an earlier title, reuse its slug
+			_, err = slugCol.UpdateOne(ctx, bson.M{"_id": existing.ID}, bson.M{"$set": bson.M{"isCurrent": true}})
+			if err != nil {
+				return "", err
+			}
+			slug = candidate
+		}
+	}
+
+	_, err = slugCol.UpdateMany(ctx,
+		bson.M{"entityType": entityType, "entityId": entityID, "slug": bson.M{"$ne": slug}},
+		bson.M{"$set": bson.M{"isCurrent": false}},
+	)
+	return slug, err
+}
+
+// slugMatchesBase reports whether slug is base or base with a numeric uniqueness suffix
+func slugMatchesBase(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// resolveEntityParam resolves a route parameter holding either an ObjectID or a slug.
+// GET requests using an outdated slug are redirected (301) to the current one.
+// It writes the response and returns false when the handler should stop.
+func resolveEntityParam(c *gin.Context, slugCol *mongo.Collection, entityType, param string) (primitive.ObjectID, bool) {
+	value := c.Param(param)
+	if objID, err := primitive.ObjectIDFromHex(value); err == nil {
+		return objID, true
+	}
+
+	var record models.Slug
+	err := slugCol.FindOne(context.TODO(), bson.M{"entityType": entityType, "slug": value}).Decode(&record)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return primitive.NilObjectID, false
+	}
+
+	if !record.IsCurrent && c.Request.Method == http.MethodGet {
+		var current models.Slug
+		err := slugCol.FindOne(context.TODO(), bson.M{"entityType": entityType, "entityId": record.EntityID, "isCurrent": true}).Decode(&current)
+		if err == nil {
+			target := *c.Request.URL
+			target.Path = strings.Replace(target.Path, "/"+value, "/"+current.Slug, 1)
+			c.Redirect(http.StatusMovedPermanently, target.String())
+			return primitive.NilObjectID, false
+		}
+	}
+
+	return record.EntityID, true
+}
+
+// resolveAudiobookParam resolves the :id route parameter of audiobook routes
+func resolveAudiobookParam(c *gin.Context, slugCol *mongo.Collection) (primitive.ObjectID, bool) {
+	return resolveEntityParam(c, slugCol, models.SlugEntityAudiobook, "id")
+}
+
+// updateAudiobookSlug assigns a slug for the audiobook's name and stores it on the audiobook
+func (ac *AudiobookController) updateAudiobookSlug(audiobookID primitive.ObjectID, name string) {
+	slug, err := assignSlug(context.TODO(), ac.SlugCol, models.SlugEntityAudiobook, audiobookID, name)
+	if err != nil {
+		log.Printf("Failed to assign slug for audiobook %s: %v", audiobookID.Hex(), err)
+		return
+	}
+	ac.AudiobookCol.UpdateOne(context.TODO(), bson.M{"_id": audiobookID}, bson.M{"$set": bson.M{"slug": slug}})
+}
+
+// BackfillAudiobookSlugs assigns slugs to audiobooks created before slugs existed
+func (ac *AudiobookController) BackfillAudiobookSlugs() {
+	cursor, err := ac.AudiobookCol.Find(context.TODO(), bson.M{"slug": bson.M{"$in": bson.A{nil, ""}}})
+	if err != nil {
+		log.Println("Slug backfill: failed to fetch audiobooks:", err)
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var audiobook models.Audiobook
+		if err := cursor.Decode(&audiobook); err == nil {
+			ac.updateAudiobookSlug(audiobook.ID, audiobook.Name)
+		}
+	}
+}
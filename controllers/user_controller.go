@@ -4,8 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strings"
-
-	// "time"
+	"time"
 
 	models "live_stream/models"
 	request "live_stream/models/requests"
@@ -19,9 +18,15 @@ import (
 )
 
 type UserController struct {
-	UserCol   *mongo.Collection
-	StreamCol *mongo.Collection // Now refers to audiobooks collection
-	Redis     *redis.Client
+	UserCol      *mongo.Collection
+	StreamCol    *mongo.Collection // Now refers to audiobooks collection
+	ProgressCol  *mongo.Collection
+	BookmarkCol  *mongo.Collection
+	ShelfCol     *mongo.Collection
+	ShelfItemCol *mongo.Collection
+	SessionCol   *mongo.Collection
+	EventCol     *mongo.Collection
+	Redis        *redis.Client
 }
 
 func (uc *UserController) GetProfile(c *gin.Context) {
@@ -85,6 +90,101 @@ func (uc *UserController) ChangePassword(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Password updated successfully"})
 }
 
+// UpdateContentPreferences sets the maximum age rating and hidden content warnings for the user
+func (uc *UserController) UpdateContentPreferences(c *gin.Context) {
+	var req request.ContentPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.MaxAgeRating != "" && !isValidAgeRating(req.MaxAgeRating) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid age rating"})
+		return
+	}
+
+	objID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	hidden := normalizeTags(req.HiddenWarnings)
+	_, err = uc.UserCol.UpdateOne(
+		context.TODO(),
+		bson.M{"_id": objID},
+		bson.M{"$set": bson.M{
+			"maxAgeRating":   req.MaxAgeRating,
+			"hiddenWarnings": hidden,
+			"updatedAt":      time.Now(),
+		}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update content preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Content preferences updated",
+		"maxAgeRating":   req.MaxAgeRating,
+		"hiddenWarnings": hidden,
+	})
+}
+
+// FollowAuthor adds an author to the user's followed authors
+func (uc *UserController) FollowAuthor(c *gin.Context) {
+	var req request.FollowAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	author := strings.TrimSpace(req.Author)
+	if author == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Author is required"})
+		return
+	}
+
+	objID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	_, err = uc.UserCol.UpdateOne(
+		context.TODO(),
+		bson.M{"_id": objID},
+		bson.M{"$addToSet": bson.M{"followedAuthors": author}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow author"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Author followed"})
+}
+
+// UnfollowAuthor removes an author from the user's followed authors
+func (uc *UserController) UnfollowAuthor(c *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	_, err = uc.UserCol.UpdateOne(
+		context.TODO(),
+		bson.M{"_id": objID},
+		bson.M{"$pull": bson.M{"followedAuthors": c.Param("author")}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow author"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Author unfollowed"})
+}
+
 func (uc *UserController) GetActiveUsers(c *gin.Context) {
 	var activeUsers []models.User
 
@@ -257,7 +357,7 @@ func (uc *UserController) GetDashboardStats(c *gin.Context) {
 	activeUsers := len(keys)
 
 	// 3️⃣ Total Audiobooks (changed from streams)
-	totalAudiobooks, err := uc.StreamCol.CountDocuments(ctx, bson.M{})
+	totalAudiobooks, err := uc.StreamCol.CountDocuments(ctx, bson.M{"deletedAt": nil})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count audiobooks"})
 		return
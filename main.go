@@ -7,6 +7,7 @@ import (
 	"live_stream/route"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -33,6 +34,13 @@ func main() {
 		dbName = "streamapp"
 	}
 
+	config.EnsureIndexes(mongoClient.Database(dbName))
+
+	trashRetentionDays, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || trashRetentionDays <= 0 {
+		trashRetentionDays = 30
+	}
+
 	// -------------------------
 	// Initialize Controllers
 	// -------------------------
@@ -42,19 +50,68 @@ func main() {
 	}
 
 	userCtrl := &controllers.UserController{
-		UserCol:   mongoClient.Database(dbName).Collection("users"),
-		StreamCol: mongoClient.Database(dbName).Collection("audiobooks"), // Changed from streams
-		Redis:     redisClient,
+		UserCol:      mongoClient.Database(dbName).Collection("users"),
+		StreamCol:    mongoClient.Database(dbName).Collection("audiobooks"), // Changed from streams
+		ProgressCol:  mongoClient.Database(dbName).Collection("playback_progress"),
+		BookmarkCol:  mongoClient.Database(dbName).Collection("bookmarks"),
+		ShelfCol:     mongoClient.Database(dbName).Collection("shelves"),
+		ShelfItemCol: mongoClient.Database(dbName).Collection("shelf_items"),
+		SessionCol:   mongoClient.Database(dbName).Collection("listening_sessions"),
+		EventCol:     mongoClient.Database(dbName).Collection("ingested_events"),
+		Redis:        redisClient,
 	}
 
 	audiobookCtrl := &controllers.AudiobookController{
 		AudiobookCol:   mongoClient.Database(dbName).Collection("audiobooks"),
 		InteractionCol: mongoClient.Database(dbName).Collection("audiobook_interactions"),
+		ReactionCol:    mongoClient.Database(dbName).Collection("audiobook_reactions"),
+		RevisionCol:    mongoClient.Database(dbName).Collection("audiobook_revisions"),
+		CommentCol:     mongoClient.Database(dbName).Collection("comments"),
+		ReviewCol:      mongoClient.Database(dbName).Collection("reviews"),
+		ReviewVoteCol:  mongoClient.Database(dbName).Collection("review_votes"),
+		ImportJobCol:   mongoClient.Database(dbName).Collection("import_jobs"),
+		SlugCol:        mongoClient.Database(dbName).Collection("slugs"),
+		UserCol:        mongoClient.Database(dbName).Collection("users"),
+		RelatedCol:     mongoClient.Database(dbName).Collection("related_audiobooks"),
+		CollectionCol:  mongoClient.Database(dbName).Collection("collections"),
+		ProgressCol:    mongoClient.Database(dbName).Collection("playback_progress"),
+		BookmarkCol:    mongoClient.Database(dbName).Collection("bookmarks"),
+		ShelfItemCol:   mongoClient.Database(dbName).Collection("shelf_items"),
+		SessionCol:     mongoClient.Database(dbName).Collection("listening_sessions"),
+		PlaylistCol:    mongoClient.Database(dbName).Collection("playlists"),
+		QueueCol:       mongoClient.Database(dbName).Collection("listening_queues"),
+		Redis:          redisClient,
+		TrashRetention: time.Duration(trashRetentionDays) * 24 * time.Hour,
 	}
 
 	commentCtrl := &controllers.CommentController{
-		CommentCol: mongoClient.Database(dbName).Collection("comments"),
-		UserCol:    mongoClient.Database(dbName).Collection("users"),
+		CommentCol:   mongoClient.Database(dbName).Collection("comments"),
+		AudiobookCol: mongoClient.Database(dbName).Collection("audiobooks"),
+		UserCol:      mongoClient.Database(dbName).Collection("users"),
+		SlugCol:      mongoClient.Database(dbName).Collection("slugs"),
+		Redis:        redisClient,
+	}
+
+	collectionCtrl := &controllers.CollectionController{
+		CollectionCol: mongoClient.Database(dbName).Collection("collections"),
+		AudiobookCol:  mongoClient.Database(dbName).Collection("audiobooks"),
+		SlugCol:       mongoClient.Database(dbName).Collection("slugs"),
+		UserCol:       mongoClient.Database(dbName).Collection("users"),
+	}
+
+	reviewCtrl := &controllers.ReviewController{
+		ReviewCol:     mongoClient.Database(dbName).Collection("reviews"),
+		ReviewVoteCol: mongoClient.Database(dbName).Collection("review_votes"),
+		AudiobookCol:  mongoClient.Database(dbName).Collection("audiobooks"),
+		UserCol:       mongoClient.Database(dbName).Collection("users"),
+		SlugCol:       mongoClient.Database(dbName).Collection("slugs"),
+	}
+
+	playlistCtrl := &controllers.PlaylistController{
+		PlaylistCol:  mongoClient.Database(dbName).Collection("playlists"),
+		QueueCol:     mongoClient.Database(dbName).Collection("listening_queues"),
+		AudiobookCol: mongoClient.Database(dbName).Collection("audiobooks"),
+		UserCol:      mongoClient.Database(dbName).Collection("users"),
 	}
 
 	adCtrl := &controllers.AdController{
@@ -65,6 +122,21 @@ func main() {
 		SiteChangesCol: mongoClient.Database(dbName).Collection("site_change"),
 	}
 
+	// -------------------------
+	// Start Background Workers
+	// -------------------------
+	go audiobookCtrl.RunPublishScheduler(time.Minute)
+	go audiobookCtrl.RunTrashPurger(time.Hour)
+	go audiobookCtrl.BackfillAudiobookSlugs()
+	go audiobookCtrl.BackfillAudiobookStatus()
+	go audiobookCtrl.RunImportJobReaper(5 * time.Minute)
+	go audiobookCtrl.RunRelatedTitlesJob(6 * time.Hour)
+	go audiobookCtrl.RunTrendingJob(15 * time.Minute)
+	go audiobookCtrl.RunViewCountFlusher(time.Minute)
+	go audiobookCtrl.RunReactionReconciler(time.Hour)
+	go commentCtrl.BackfillCommentThreads()
+	go commentCtrl.RunCommentHub()
+
 	// -------------------------
 	// Initialize Middleware
 	// -------------------------
@@ -98,7 +170,7 @@ func main() {
 	// -------------------------
 	// Setup All Routes
 	// -------------------------
-	route.SetupRoutes(router, redisClient, authCtrl, userCtrl, audiobookCtrl, commentCtrl, adCtrl, siteCtrl)
+	route.SetupRoutes(router, redisClient, authCtrl, userCtrl, audiobookCtrl, commentCtrl, collectionCtrl, reviewCtrl, playlistCtrl, adCtrl, siteCtrl)
 
 	// -------------------------
 	// Start Server
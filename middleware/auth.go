@@ -57,6 +57,30 @@ func AuthMiddleware(redis *redis.Client) gin.HandlerFunc {
 	}
 }
 
+// OptionalAuthMiddleware sets user_id when a valid session token is present,
+// but lets anonymous requests through so public routes can personalize their response
+func OptionalAuthMiddleware(redis *redis.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.Next()
+			return
+		}
+
+		claims, err := utils.ValidateJWT(parts[1])
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		exists, err := redis.Exists(context.TODO(), "session:"+claims.UserID).Result()
+		if err == nil && exists > 0 {
+			c.Set("user_id", claims.UserID)
+		}
+		c.Next()
+	}
+}
+
 // AdminMiddleware validates JWT and checks if user is admin
 func AdminMiddleware(redis *redis.Client) gin.HandlerFunc {
 	return func(c *gin.Context) {
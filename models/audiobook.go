@@ -6,17 +6,60 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Audiobook publishing lifecycle states
+const (
+	AudiobookStatusDraft       = "draft"
+	AudiobookStatusInReview    = "in_review"
+	AudiobookStatusScheduled   = "scheduled"
+	AudiobookStatusPublished   = "published"
+	AudiobookStatusUnpublished = "unpublished"
+)
+
+// AudiobookTranslation holds the catalog text of an audiobook in one locale.
+// Empty fields fall back to the audiobook's own text.
+type AudiobookTranslation struct {
+	Name        string `bson:"name,omitempty" json:"name,omitempty"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+	Content     string `bson:"content,omitempty" json:"content,omitempty"`
+}
+
+// Age ratings from least to most restrictive audience
+var AgeRatings = []string{"all", "7+", "13+", "16+", "18+"}
+
 type Audiobook struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Name          string             `bson:"name" json:"name"`
-	Description   string             `bson:"description" json:"description"`
-	AudioData     string             `bson:"audioData" json:"audioData"`         // Base64 encoded audio or file path
-	Thumbnail     string             `bson:"thumbnail" json:"thumbnail"`         // Predefined thumbnail name or base64/URL
-	Content       string             `bson:"content" json:"content"`             // Transcription/content of the audiobook
-	ViewCount     int                `bson:"viewCount" json:"viewCount"`         // Total views
-	Likes         int                `bson:"likes" json:"likes"`                 // Like count
-	Dislikes      int                `bson:"dislikes" json:"dislikes"`           // Dislike count
-	DisplayOnSite bool               `bson:"displayOnSite" json:"displayOnSite"` // Visibility flag
-	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID                 primitive.ObjectID              `bson:"_id,omitempty" json:"id,omitempty"`
+	ExternalID         string                          `bson:"externalId,omitempty" json:"externalId,omitempty"` // Publisher's ID, used to make bulk imports idempotent
+	Name               string                          `bson:"name" json:"name"`
+	Slug               string                          `bson:"slug,omitempty" json:"slug,omitempty"` // Current URL slug, usable instead of the ID on public routes
+	Description        string                          `bson:"description" json:"description"`
+	AudioData          string                          `bson:"audioData" json:"audioData"`                                 // Base64 encoded audio or file path
+	Thumbnail          string                          `bson:"thumbnail" json:"thumbnail"`                                 // Predefined thumbnail name or base64/URL
+	DurationSeconds    int                             `bson:"durationSeconds,omitempty" json:"durationSeconds,omitempty"` // Total running time, used to compute listening progress
+	Content            string                          `bson:"content" json:"content"`                                     // Transcription/content of the audiobook
+	Language           string                          `bson:"language,omitempty" json:"language,omitempty"`               // Spoken language (BCP 47), also the language of Name/Description/Content
+	Translations       map[string]AudiobookTranslation `bson:"translations,omitempty" json:"translations,omitempty"`       // Catalog text keyed by locale
+	Locale             string                          `bson:"-" json:"locale,omitempty"`                                  // Locale the text was served in, set on public responses
+	ViewCount          int                             `bson:"viewCount" json:"viewCount"`                                 // Total views
+	Likes              int                             `bson:"likes" json:"likes"`                                         // Like count
+	Dislikes           int                             `bson:"dislikes" json:"dislikes"`                                   // Dislike count
+	RatingAverage      float64                         `bson:"ratingAverage" json:"ratingAverage"`                         // Mean of visible star ratings
+	RatingCount        int                             `bson:"ratingCount" json:"ratingCount"`                             // Number of visible ratings
+	RatingDistribution [5]int                          `bson:"ratingDistribution" json:"ratingDistribution"`               // Visible ratings per star, index 0 is 1 star
+	Authors            []string                        `bson:"authors,omitempty" json:"authors,omitempty"`
+	Genres             []string                        `bson:"genres,omitempty" json:"genres,omitempty"`                   // Lowercase genre tags, e.g. "thriller"
+	AgeRating          string                          `bson:"ageRating,omitempty" json:"ageRating,omitempty"`             // One of AgeRatings, unrated is treated as "all"
+	ContentWarnings    []string                        `bson:"contentWarnings,omitempty" json:"contentWarnings,omitempty"` // e.g. "violence", "language"
+	DisplayOnSite      bool                            `bson:"displayOnSite" json:"displayOnSite"`                         // Visibility flag, kept in sync with Status
+	Status             string                          `bson:"status" json:"status"`                                       // Lifecycle state (draft, in_review, scheduled, published, unpublished)
+	PublishAt          *time.Time                      `bson:"publishAt,omitempty" json:"publishAt,omitempty"`
+	UnpublishAt        *time.Time                      `bson:"unpublishAt,omitempty" json:"unpublishAt,omitempty"`
+	PublishedAt        *time.Time                      `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
+	SubmittedBy        *primitive.ObjectID             `bson:"submittedBy,omitempty" json:"submittedBy,omitempty"` // Admin who submitted it for review, who can't approve it
+	ReviewedBy         *primitive.ObjectID             `bson:"reviewedBy,omitempty" json:"reviewedBy,omitempty"`   // Admin who approved/rejected
+	ReviewedAt         *time.Time                      `bson:"reviewedAt,omitempty" json:"reviewedAt,omitempty"`
+	ReviewNote         string                          `bson:"reviewNote,omitempty" json:"reviewNote,omitempty"`
+	DeletedAt          *time.Time                      `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"` // Set while the audiobook is in the trash
+	DeletedBy          *primitive.ObjectID             `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+	CreatedAt          time.Time                       `bson:"createdAt" json:"createdAt"`
+	UpdatedAt          time.Time                       `bson:"updatedAt" json:"updatedAt"`
 }
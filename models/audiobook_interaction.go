@@ -10,6 +10,6 @@ type AudiobookInteraction struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	AudiobookID primitive.ObjectID `bson:"audiobookId" json:"audiobookId"` // Reference to audiobook
 	UserID      primitive.ObjectID `bson:"userId" json:"userId"`           // Reference to user
-	Action      string             `bson:"action" json:"action"`           // "like", "dislike", "view"
+	Action      string             `bson:"action" json:"action"`           // "view" (likes and dislikes are AudiobookReactions)
 	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
 }
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ReactionLike    = "like"
+	ReactionDislike = "dislike"
+)
+
+// AudiobookReaction is a user's single like or dislike of an audiobook.
+// (audiobookId, userId) is unique, so a user holds at most one reaction per title.
+type AudiobookReaction struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	AudiobookID primitive.ObjectID `bson:"audiobookId" json:"audiobookId"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	Reaction    string             `bson:"reaction" json:"reaction"` // ReactionLike or ReactionDislike
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
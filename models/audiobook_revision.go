@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MediaSnapshot identifies the audio or thumbnail of an audiobook without copying inline data,
+// which can be megabytes of base64
+type MediaSnapshot struct {
+	Hash string `bson:"hash,omitempty" json:"hash,omitempty"` // SHA-256 of the stored value, empty when there is none
+	Ref  string `bson:"ref,omitempty" json:"ref,omitempty"`   // The value itself when it is a short path, URL or name
+}
+
+// AudiobookMetadata is the editable part of an audiobook captured in each revision
+type AudiobookMetadata struct {
+	Name            string                          `bson:"name" json:"name"`
+	Description     string                          `bson:"description" json:"description"`
+	Audio           MediaSnapshot                   `bson:"audio" json:"audio"`
+	Thumbnail       MediaSnapshot                   `bson:"thumbnail" json:"thumbnail"`
+	Content         string                          `bson:"content" json:"content"`
+	Language        string                          `bson:"language,omitempty" json:"language,omitempty"`
+	Translations    map[string]AudiobookTranslation `bson:"translations,omitempty" json:"translations,omitempty"`
+	Authors         []string                        `bson:"authors,omitempty" json:"authors,omitempty"`
+	Genres          []string                        `bson:"genres,omitempty" json:"genres,omitempty"`
+	AgeRating       string                          `bson:"ageRating,omitempty" json:"ageRating,omitempty"`
+	ContentWarnings []string                        `bson:"contentWarnings,omitempty" json:"contentWarnings,omitempty"`
+}
+
+type AudiobookRevision struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	AudiobookID    primitive.ObjectID  `bson:"audiobookId" json:"audiobookId"`
+	Revision       int                 `bson:"revision" json:"revision"`                     // Sequential per audiobook, starting at 1
+	Metadata       AudiobookMetadata   `bson:"metadata" json:"metadata"`                     // Snapshot after this revision was applied
+	EditedBy       *primitive.ObjectID `bson:"editedBy,omitempty" json:"editedBy,omitempty"` // Admin who made the change, nil for baseline snapshots
+	RolledBackFrom int                 `bson:"rolledBackFrom,omitempty" json:"rolledBackFrom,omitempty"`
+	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bookmark types
+const (
+	BookmarkTypeBookmark = "bookmark" // A single position
+	BookmarkTypeClip     = "clip"     // A range from PositionSeconds to EndSeconds
+)
+
+// Bookmark is a moment (or clip) a user saved inside an audiobook, with an optional note
+type Bookmark struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID          primitive.ObjectID `bson:"userId" json:"userId"`
+	AudiobookID     primitive.ObjectID `bson:"audiobookId" json:"audiobookId"`
+	Type            string             `bson:"type" json:"type"`
+	PositionSeconds float64            `bson:"positionSeconds" json:"positionSeconds"`           // Bookmark position, or clip start
+	EndSeconds      float64            `bson:"endSeconds,omitempty" json:"endSeconds,omitempty"` // Clip end
+	Chapter         int                `bson:"chapter" json:"chapter"`
+	Note            string             `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Collection types
+const (
+	CollectionTypeCurated = "curated" // Hand-picked, ordered list of audiobooks
+	CollectionTypeSmart   = "smart"   // Filled from SmartCollectionRule at request time
+)
+
+// SmartCollectionRule selects the audiobooks of a smart collection, e.g. "new in thriller"
+type SmartCollectionRule struct {
+	Genre               string `bson:"genre,omitempty" json:"genre,omitempty"`
+	Language            string `bson:"language,omitempty" json:"language,omitempty"`
+	PublishedWithinDays int    `bson:"publishedWithinDays,omitempty" json:"publishedWithinDays,omitempty"`
+	SortBy              string `bson:"sortBy,omitempty" json:"sortBy,omitempty"` // "newest" (default), "popular" or "liked"
+	Limit               int    `bson:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// Collection is an editorial shelf shown on the homepage
+type Collection struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	Title        string               `bson:"title" json:"title"`
+	Slug         string               `bson:"slug,omitempty" json:"slug,omitempty"`
+	Description  string               `bson:"description" json:"description"`
+	Artwork      string               `bson:"artwork" json:"artwork"`
+	Type         string               `bson:"type" json:"type"`
+	AudiobookIDs []primitive.ObjectID `bson:"audiobookIds,omitempty" json:"audiobookIds,omitempty"` // Curated collections, in display order
+	Rule         *SmartCollectionRule `bson:"rule,omitempty" json:"rule,omitempty"`                 // Smart collections
+	Position     int                  `bson:"position" json:"position"`                             // Order of the shelf on the homepage
+	IsActive     bool                 `bson:"isActive" json:"isActive"`
+	StartsAt     *time.Time           `bson:"startsAt,omitempty" json:"startsAt,omitempty"` // Shelf is only shown inside this window
+	EndsAt       *time.Time           `bson:"endsAt,omitempty" json:"endsAt,omitempty"`
+	CreatedAt    time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
@@ -6,14 +6,33 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// CommentEdit is a previous version of an edited comment
+type CommentEdit struct {
+	Message  string             `bson:"message" json:"message"`
+	EditedAt time.Time          `bson:"editedAt" json:"editedAt"` // When this version was replaced
+	EditedBy primitive.ObjectID `bson:"editedBy" json:"editedBy"`
+}
+
+// MaxCommentDepth is how deeply replies nest; replies to the deepest level join their parent's thread
+const MaxCommentDepth = 3
+
 type Comment struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	AudiobookID primitive.ObjectID `bson:"audiobookId" json:"audiobookId"` // Changed from StreamID
-	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
-	UserName    string             `bson:"userName" json:"userName"`
-	Message     string             `bson:"message" json:"message"`
-	IsAdmin     bool               `bson:"isAdmin" json:"isAdmin"`
-	Timestamp   time.Time          `bson:"timestamp" json:"timestamp"`
-	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
-	IsDeleted   bool               `bson:"isDeleted" json:"isDeleted"`
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	AudiobookID primitive.ObjectID  `bson:"audiobookId" json:"audiobookId"`                             // Changed from StreamID
+	ParentID    *primitive.ObjectID `bson:"parentId,omitempty" json:"parentId,omitempty"`               // Comment this replies to, nil for top-level comments
+	Depth       int                 `bson:"depth" json:"depth"`                                         // 0 for top-level comments
+	ReplyCount  int                 `bson:"replyCount" json:"replyCount"`                               // Direct replies
+	Position    *float64            `bson:"positionSeconds,omitempty" json:"positionSeconds,omitempty"` // Moment on the audio timeline the comment is about
+	Chapter     *int                `bson:"chapter,omitempty" json:"chapter,omitempty"`
+	UserID      primitive.ObjectID  `bson:"userId" json:"userId"`
+	UserName    string              `bson:"userName" json:"userName"`
+	Message     string              `bson:"message" json:"message"`
+	IsAdmin     bool                `bson:"isAdmin" json:"isAdmin"`
+	Timestamp   time.Time           `bson:"timestamp" json:"timestamp"`
+	UpdatedAt   time.Time           `bson:"updatedAt" json:"updatedAt"`
+	EditedAt    *time.Time          `bson:"editedAt,omitempty" json:"editedAt,omitempty"`
+	Edits       []CommentEdit       `bson:"edits,omitempty" json:"-"`   // Edit history, oldest first, served separately
+	IsDeleted   bool                `bson:"isDeleted" json:"isDeleted"` // Deleted comments remain as tombstones while they have replies
+	DeletedAt   *time.Time          `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy   *primitive.ObjectID `bson:"deletedBy,omitempty" json:"-"`
 }
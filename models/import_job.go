@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Import job states
+const (
+	ImportJobPending   = "pending"
+	ImportJobRunning   = "running"
+	ImportJobCompleted = "completed"
+	ImportJobFailed    = "failed"
+)
+
+// ImportRowError reports a problem with a single manifest row
+type ImportRowError struct {
+	Row        int    `bson:"row" json:"row"` // 1-based, excluding the CSV header
+	ExternalID string `bson:"externalId" json:"externalId"`
+	Error      string `bson:"error" json:"error"`
+}
+
+type ImportJob struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Format    string             `bson:"format" json:"format"` // "csv" or "json"
+	Status    string             `bson:"status" json:"status"`
+	TotalRows int                `bson:"totalRows" json:"totalRows"`
+	Processed int                `bson:"processed" json:"processed"`
+	Created   int                `bson:"created" json:"created"`
+	Updated   int                `bson:"updated" json:"updated"`
+	Unchanged int                `bson:"unchanged" json:"unchanged"`
+	Failed    int                `bson:"failed" json:"failed"`
+	Errors    []ImportRowError   `bson:"errors" json:"errors"`
+	Error     string             `bson:"error,omitempty" json:"error,omitempty"` // Why the whole job failed, e.g. the server stopped mid-import
+	CreatedBy primitive.ObjectID `bson:"createdBy" json:"createdBy"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IngestedEventRetention is how long event IDs are remembered; older events can't be ingested
+const IngestedEventRetention = 30 * 24 * time.Hour
+
+// IngestedEventPending marks an event ID claimed by a request that has not applied it yet
+const IngestedEventPending = "pending"
+
+// IngestedEvent remembers a client event ID that was already processed, so replays of an
+// offline event log are ignored. (userId, eventId) is unique; markers expire after IngestedEventRetention.
+type IngestedEvent struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	EventID   string             `bson:"eventId" json:"eventId"`
+	Type      string             `bson:"type" json:"type"`
+	Status    string             `bson:"status" json:"status"` // Pending until applied, then the ingest outcome
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ListeningSession is one continuous stretch of listening reported by a player
+type ListeningSession struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID          primitive.ObjectID `bson:"userId" json:"userId"`
+	AudiobookID     primitive.ObjectID `bson:"audiobookId" json:"audiobookId"`
+	StartedAt       time.Time          `bson:"startedAt" json:"startedAt"`
+	EndedAt         time.Time          `bson:"endedAt" json:"endedAt"`
+	SecondsListened int                `bson:"secondsListened" json:"secondsListened"` // Audio time heard; differs from wall time at other speeds or with pauses
+	DeviceID        string             `bson:"deviceId,omitempty" json:"deviceId,omitempty"`
+	EventID         string             `bson:"eventId,omitempty" json:"-"` // Client event it was ingested from, unique per user
+	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
+}
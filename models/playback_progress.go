@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PlaybackProgress is where a user is in an audiobook, shared across their devices.
+// (userId, audiobookId) is unique; the most recent update wins.
+type PlaybackProgress struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID          primitive.ObjectID `bson:"userId" json:"userId"`
+	AudiobookID     primitive.ObjectID `bson:"audiobookId" json:"audiobookId"`
+	PositionSeconds float64            `bson:"positionSeconds" json:"positionSeconds"`
+	Chapter         int                `bson:"chapter" json:"chapter"`
+	PlaybackSpeed   float64            `bson:"playbackSpeed" json:"playbackSpeed"`
+	Completed       bool               `bson:"completed" json:"completed"`
+	DeviceID        string             `bson:"deviceId,omitempty" json:"deviceId,omitempty"` // Device that wrote the current position
+	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`                   // When the position was reached, on the server clock
+}
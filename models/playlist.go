@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Playlist visibility
+const (
+	PlaylistPrivate = "private" // Owner and collaborators only
+	PlaylistPublic  = "public"  // Anyone with the share link
+)
+
+// PlaylistItem is a whole audiobook, or one of its chapters, in a playlist or queue
+type PlaylistItem struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	AudiobookID primitive.ObjectID `bson:"audiobookId" json:"audiobookId"`
+	Chapter     int                `bson:"chapter,omitempty" json:"chapter,omitempty"` // 0 plays the whole audiobook
+	AddedBy     primitive.ObjectID `bson:"addedBy,omitempty" json:"addedBy,omitempty"`
+	AddedAt     time.Time          `bson:"addedAt" json:"addedAt"`
+}
+
+// Playlist is a user-created, ordered list of audiobooks or chapters.
+// Collaborators may change the items; only the owner may change anything else.
+type Playlist struct {
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	OwnerID       primitive.ObjectID   `bson:"ownerId" json:"ownerId"`
+	Title         string               `bson:"title" json:"title"`
+	Description   string               `bson:"description,omitempty" json:"description,omitempty"`
+	Visibility    string               `bson:"visibility" json:"visibility"`
+	ShareToken    string               `bson:"shareToken,omitempty" json:"shareToken,omitempty"` // Public playlists are shared via /api/playlists/shared/:token
+	Collaborators []primitive.ObjectID `bson:"collaborators" json:"collaborators"`
+	Items         []PlaylistItem       `bson:"items" json:"items"`
+	Version       int                  `bson:"version" json:"version"` // Bumped on every change, for conflict-free collaborative edits
+	CreatedAt     time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ListeningQueue is a user's server-side "up next" list, shared by all their devices
+type ListeningQueue struct {
+	UserID    primitive.ObjectID `bson:"_id" json:"userId"`
+	Items     []PlaylistItem     `bson:"items" json:"items"`
+	Version   int                `bson:"version" json:"version"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RelatedAudiobook struct {
+	AudiobookID primitive.ObjectID `bson:"audiobookId" json:"audiobookId"`
+	Score       float64            `bson:"score" json:"score"`
+}
+
+// RelatedAudiobooks is the precomputed list of titles related to one audiobook,
+// refreshed periodically from co-engagement and metadata similarity
+type RelatedAudiobooks struct {
+	AudiobookID primitive.ObjectID `bson:"_id" json:"audiobookId"`
+	Related     []RelatedAudiobook `bson:"related" json:"related"` // Best match first
+	ComputedAt  time.Time          `bson:"computedAt" json:"computedAt"`
+}
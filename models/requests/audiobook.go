@@ -1,24 +1,74 @@
 package models
 
+import "time"
+
 // Audiobook requests
 type CreateAudiobookRequest struct {
-	Name          string `json:"name" binding:"required"`
-	Description   string `json:"description" binding:"required"`
-	AudioData     string `json:"audioData" binding:"required"` // Base64 encoded audio or file path
-	Thumbnail     string `json:"thumbnail"`
-	Content       string `json:"content"` // Transcription/content
-	DisplayOnSite bool   `json:"displayOnSite"`
+	Name            string                        `json:"name" binding:"required"`
+	Description     string                        `json:"description" binding:"required"`
+	AudioData       string                        `json:"audioData" binding:"required"` // Base64 encoded audio or file path
+	Thumbnail       string                        `json:"thumbnail"`
+	Content         string                        `json:"content"`  // Transcription/content
+	Language        string                        `json:"language"` // Spoken language, BCP 47 (e.g. "hi", "en")
+	Translations    map[string]TranslationRequest `json:"translations"`
+	Authors         []string                      `json:"authors"`
+	Genres          []string                      `json:"genres"`
+	AgeRating       string                        `json:"ageRating"`
+	ContentWarnings []string                      `json:"contentWarnings"`
+	DurationSeconds int                           `json:"durationSeconds"`
+	DisplayOnSite   *bool                         `json:"displayOnSite"` // Not settable: visibility follows the publishing workflow
 }
 
 type UpdateAudiobookRequest struct {
-	Name          string `json:"name"`
-	Description   string `json:"description"`
-	AudioData     string `json:"audioData"`
-	Thumbnail     string `json:"thumbnail"`
-	Content       string `json:"content"`
-	DisplayOnSite *bool  `json:"displayOnSite"`
+	Name            string                        `json:"name"`
+	Description     string                        `json:"description"`
+	AudioData       string                        `json:"audioData"`
+	Thumbnail       string                        `json:"thumbnail"`
+	Content         string                        `json:"content"`
+	Language        string                        `json:"language"`
+	Translations    map[string]TranslationRequest `json:"translations"` // Replaces all translations when present
+	Authors         []string                      `json:"authors"`      // Replaces all authors when present
+	Genres          []string                      `json:"genres"`       // Replaces all genres when present
+	AgeRating       string                        `json:"ageRating"`
+	ContentWarnings []string                      `json:"contentWarnings"` // Replaces all warnings when present
+	DurationSeconds int                           `json:"durationSeconds"`
+	DisplayOnSite   *bool                         `json:"displayOnSite"` // Not settable: visibility follows the publishing workflow
+}
+
+// TranslationRequest is the catalog text of an audiobook in one locale
+type TranslationRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Content     string `json:"content"`
 }
 
 type LikeDislikeRequest struct {
 	Action string `json:"action" binding:"required"` // "like" or "dislike"
 }
+
+// ScheduleAudiobookRequest sets the publish/unpublish window of an audiobook
+type ScheduleAudiobookRequest struct {
+	PublishAt   *time.Time `json:"publishAt"`
+	UnpublishAt *time.Time `json:"unpublishAt"`
+}
+
+// ReviewAudiobookRequest carries the reviewer's note on approve/reject
+type ReviewAudiobookRequest struct {
+	Note string `json:"note"`
+}
+
+// ImportAudiobookRow is one entry of a bulk import manifest (CSV columns use the same names as the JSON keys).
+// AudioData and Thumbnail may name a media file uploaded alongside the manifest.
+type ImportAudiobookRow struct {
+	ExternalID  string `json:"externalId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	AudioData   string `json:"audioData"`
+	Thumbnail   string `json:"thumbnail"`
+	Content     string `json:"content"`
+}
+
+// RecordPlayRequest reports how long a listener played an audiobook in one session
+type RecordPlayRequest struct {
+	SecondsListened int `json:"secondsListened" binding:"required"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SmartCollectionRuleRequest struct {
+	Genre               string `json:"genre"`
+	Language            string `json:"language"`
+	PublishedWithinDays int    `json:"publishedWithinDays"`
+	SortBy              string `json:"sortBy"`
+	Limit               int    `json:"limit"`
+}
+
+type CreateCollectionRequest struct {
+	Title        string                      `json:"title" binding:"required"`
+	Description  string                      `json:"description"`
+	Artwork      string                      `json:"artwork"`
+	Type         string                      `json:"type" binding:"required"` // "curated" or "smart"
+	AudiobookIDs []primitive.ObjectID        `json:"audiobookIds"`
+	Rule         *SmartCollectionRuleRequest `json:"rule"`
+	Position     int                         `json:"position"`
+	IsActive     bool                        `json:"isActive"`
+	StartsAt     *time.Time                  `json:"startsAt"`
+	EndsAt       *time.Time                  `json:"endsAt"`
+}
+
+type UpdateCollectionRequest struct {
+	Title        string                      `json:"title"`
+	Description  *string                     `json:"description"`
+	Artwork      *string                     `json:"artwork"`
+	AudiobookIDs []primitive.ObjectID        `json:"audiobookIds"` // Replaces the list when present
+	Rule         *SmartCollectionRuleRequest `json:"rule"`
+	Position     *int                        `json:"position"`
+	IsActive     *bool                       `json:"isActive"`
+	StartsAt     OptionalTime                `json:"startsAt"` // null clears the date
+	EndsAt       OptionalTime                `json:"endsAt"`   // null clears the date
+}
+
+// OptionalTime tells a field left out of a request (Set is false) from an explicit null (Set, nil Time)
+type OptionalTime struct {
+	Set  bool
+	Time *time.Time
+}
+
+func (o *OptionalTime) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Time = nil
+		return nil
+	}
+	var t time.Time
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	o.Time = &t
+	return nil
+}
@@ -0,0 +1,48 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type CreatePlaylistRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility"` // "private" (default) or "public"
+}
+
+type UpdatePlaylistRequest struct {
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	Visibility  string  `json:"visibility"`
+}
+
+type PlaylistItemRequest struct {
+	AudiobookID primitive.ObjectID `json:"audiobookId" binding:"required"`
+	Chapter     int                `json:"chapter"`
+}
+
+// AddPlaylistItemRequest adds an item at Position (0-based), or at the end when Position is nil
+type AddPlaylistItemRequest struct {
+	PlaylistItemRequest
+	Position *int `json:"position"`
+}
+
+// ReorderItemsRequest lists every item ID in the new order. Version must match the
+// playlist/queue being edited, otherwise someone else changed it first.
+type ReorderItemsRequest struct {
+	ItemIDs []primitive.ObjectID `json:"itemIds" binding:"required"`
+	Version int                  `json:"version"`
+}
+
+type CollaboratorRequest struct {
+	UserID primitive.ObjectID `json:"userId" binding:"required"`
+}
+
+// EnqueueRequest adds an item to the queue, either to play next or at the end
+type EnqueueRequest struct {
+	PlaylistItemRequest
+	PlayNext bool `json:"playNext"`
+}
+
+// AdvanceQueueRequest names the item that finished playing, which must still be first in the queue
+type AdvanceQueueRequest struct {
+	ItemID primitive.ObjectID `json:"itemId" binding:"required"`
+}
@@ -0,0 +1,12 @@
+package models
+
+type ReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status" binding:"required"` // "visible" or "hidden"
+	Note   string `json:"note"`
+}
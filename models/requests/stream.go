@@ -19,9 +19,15 @@ type UpdateStreamRequest struct {
 }
 
 type AddCommentRequest struct {
-	AudiobookID primitive.ObjectID `json:"audiobookId" binding:"required"` // Changed from StreamID
-	Message     string             `json:"message" binding:"required"`
-	IsAdmin     bool               `json:"isAdmin"`
+	AudiobookID primitive.ObjectID  `json:"audiobookId" binding:"required"` // Changed from StreamID
+	ParentID    *primitive.ObjectID `json:"parentId"`                       // Reply to this comment
+	Position    *float64            `json:"positionSeconds"`                // Anchor the comment to this playback position
+	Chapter     *int                `json:"chapter"`
+	Message     string              `json:"message" binding:"required"`
+}
+
+type EditCommentRequest struct {
+	Message string `json:"message" binding:"required"`
 }
 
 type SiteChangesRequest struct {
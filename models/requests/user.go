@@ -1,5 +1,11 @@
 package models
 
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
 // ChangePasswordRequest represents a password change request
 type ChangePasswordRequest struct {
 	OldPassword string `json:"oldPassword" binding:"required"`
@@ -41,3 +47,87 @@ type ResetPasswordRequest struct {
 	OTP         string `json:"otp" binding:"required"`
 	NewPassword string `json:"newPassword" binding:"required"`
 }
+
+// ContentPreferencesRequest updates the user's mature content settings
+type ContentPreferencesRequest struct {
+	MaxAgeRating   string   `json:"maxAgeRating"` // Empty removes the restriction
+	HiddenWarnings []string `json:"hiddenWarnings"`
+}
+
+// FollowAuthorRequest represents a request to follow an author
+type FollowAuthorRequest struct {
+	Author string `json:"author" binding:"required"`
+}
+
+// ProgressUpdate is one position report from a player
+type ProgressUpdate struct {
+	AudiobookID     primitive.ObjectID `json:"audiobookId" binding:"required"`
+	PositionSeconds float64            `json:"positionSeconds"`
+	Chapter         int                `json:"chapter"`
+	PlaybackSpeed   float64            `json:"playbackSpeed"`
+	Completed       bool               `json:"completed"`
+	UpdatedAt       time.Time          `json:"updatedAt" binding:"required"` // Device clock when the position was reached
+}
+
+// SyncProgressRequest is a batch of position reports from one device.
+// SentAt is the device clock when the batch was sent, used to correct for clock skew.
+type SyncProgressRequest struct {
+	DeviceID string           `json:"deviceId"`
+	SentAt   time.Time        `json:"sentAt" binding:"required"`
+	Updates  []ProgressUpdate `json:"updates" binding:"required,dive"`
+}
+
+type CreateBookmarkRequest struct {
+	AudiobookID     primitive.ObjectID `json:"audiobookId" binding:"required"`
+	Type            string             `json:"type"` // "bookmark" (default) or "clip"
+	PositionSeconds float64            `json:"positionSeconds"`
+	EndSeconds      float64            `json:"endSeconds"` // Required for clips
+	Chapter         int                `json:"chapter"`
+	Note            string             `json:"note"`
+}
+
+type UpdateBookmarkRequest struct {
+	PositionSeconds *float64 `json:"positionSeconds"`
+	EndSeconds      *float64 `json:"endSeconds"`
+	Chapter         *int     `json:"chapter"`
+	Note            *string  `json:"note"`
+}
+
+type ShelfRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type ShelfItemRequest struct {
+	AudiobookID primitive.ObjectID `json:"audiobookId" binding:"required"`
+}
+
+type ListeningSessionRequest struct {
+	AudiobookID     primitive.ObjectID `json:"audiobookId" binding:"required"`
+	StartedAt       time.Time          `json:"startedAt" binding:"required"`
+	EndedAt         time.Time          `json:"endedAt" binding:"required"`
+	SecondsListened int                `json:"secondsListened" binding:"required"`
+	DeviceID        string             `json:"deviceId"`
+}
+
+// ClientEvent is one entry of a player's offline event log. Progress events use the position
+// fields; session events cover StartedAt until OccurredAt.
+type ClientEvent struct {
+	ID              string             `json:"id" binding:"required"`   // Client-generated, unique per user
+	Type            string             `json:"type" binding:"required"` // "progress" or "session"
+	AudiobookID     primitive.ObjectID `json:"audiobookId" binding:"required"`
+	OccurredAt      time.Time          `json:"occurredAt" binding:"required"` // Device clock
+	PositionSeconds float64            `json:"positionSeconds"`
+	Chapter         int                `json:"chapter"`
+	PlaybackSpeed   float64            `json:"playbackSpeed"`
+	Completed       bool               `json:"completed"`
+	StartedAt       time.Time          `json:"startedAt"`
+	SecondsListened int                `json:"secondsListened"`
+}
+
+// IngestEventsRequest is a batch of buffered events from one device.
+// SentAt is the device clock when the batch was sent, used to correct for clock skew.
+type IngestEventsRequest struct {
+	DeviceID string        `json:"deviceId"`
+	SentAt   time.Time     `json:"sentAt" binding:"required"`
+	Events   []ClientEvent `json:"events" binding:"required,dive"`
+}
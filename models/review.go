@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Review moderation states
+const (
+	ReviewStatusVisible = "visible"
+	ReviewStatusHidden  = "hidden" // Hidden by a moderator; left out of listings and rating aggregates
+)
+
+// Review is a user's star rating of an audiobook with an optional written review.
+// (audiobookId, userId) is unique, so a user has one editable review per title.
+type Review struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	AudiobookID    primitive.ObjectID  `bson:"audiobookId" json:"audiobookId"`
+	UserID         primitive.ObjectID  `bson:"userId" json:"userId"`
+	UserName       string              `bson:"userName" json:"userName"`
+	Rating         int                 `bson:"rating" json:"rating"` // 1-5 stars
+	Title          string              `bson:"title,omitempty" json:"title,omitempty"`
+	Body           string              `bson:"body,omitempty" json:"body,omitempty"`
+	HelpfulCount   int                 `bson:"helpfulCount" json:"helpfulCount"`
+	Status         string              `bson:"status" json:"status"`
+	ModeratedBy    *primitive.ObjectID `bson:"moderatedBy,omitempty" json:"moderatedBy,omitempty"`
+	ModeratedAt    *time.Time          `bson:"moderatedAt,omitempty" json:"moderatedAt,omitempty"`
+	ModerationNote string              `bson:"moderationNote,omitempty" json:"moderationNote,omitempty"`
+	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ReviewVote records that a user found a review helpful; (reviewId, userId) is unique
+type ReviewVote struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	ReviewID  primitive.ObjectID `bson:"reviewId" json:"reviewId"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
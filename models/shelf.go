@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Built-in shelves every user has. Custom shelves are addressed by their ID instead.
+const (
+	ShelfLibrary  = "library"  // Audiobooks the user has started or saved
+	ShelfWishlist = "wishlist" // Audiobooks the user wants to listen to later
+	ShelfFinished = "finished" // Audiobooks the user has completed
+)
+
+var BuiltInShelves = []string{ShelfLibrary, ShelfWishlist, ShelfFinished}
+
+// Shelf is a custom, user-named shelf
+type Shelf struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	Name      string             `bson:"name" json:"name"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ShelfItem places an audiobook on one of a user's shelves; (userId, shelf, audiobookId) is unique
+type ShelfItem struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	Shelf       string             `bson:"shelf" json:"shelf"` // A built-in shelf, or the hex ID of a custom Shelf
+	AudiobookID primitive.ObjectID `bson:"audiobookId" json:"audiobookId"`
+	AddedAt     time.Time          `bson:"addedAt" json:"addedAt"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Entity types that can be addressed by slug
+const (
+	SlugEntityAudiobook  = "audiobook"
+	SlugEntityCollection = "collection"
+)
+
+// Slug maps a human readable URL segment to a catalog entity.
+// Previous slugs are kept with IsCurrent false so renamed entities can redirect.
+type Slug struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Slug       string             `bson:"slug" json:"slug"`
+	EntityType string             `bson:"entityType" json:"entityType"`
+	EntityID   primitive.ObjectID `bson:"entityId" json:"entityId"`
+	IsCurrent  bool               `bson:"isCurrent" json:"isCurrent"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -8,13 +8,16 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	FullName    string             `bson:"fullName" json:"fullName"`
-	PhoneNumber string             `bson:"phoneNumber" json:"phoneNumber"`
-	Password    string             `bson:"password" json:"password,omitempty"`
-	IsAdmin     bool               `bson:"isAdmin" json:"isAdmin"`
-	IsBlocked   bool               `bson:"isBlocked" json:"isBlocked"`
-	IsVerified  bool               `bson:"isVerified" json:"isVerified"`
-	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	FullName        string             `bson:"fullName" json:"fullName"`
+	PhoneNumber     string             `bson:"phoneNumber" json:"phoneNumber"`
+	Password        string             `bson:"password" json:"password,omitempty"`
+	IsAdmin         bool               `bson:"isAdmin" json:"isAdmin"`
+	IsBlocked       bool               `bson:"isBlocked" json:"isBlocked"`
+	IsVerified      bool               `bson:"isVerified" json:"isVerified"`
+	FollowedAuthors []string           `bson:"followedAuthors,omitempty" json:"followedAuthors,omitempty"`
+	MaxAgeRating    string             `bson:"maxAgeRating,omitempty" json:"maxAgeRating,omitempty"`     // Highest age rating shown, empty means unrestricted
+	HiddenWarnings  []string           `bson:"hiddenWarnings,omitempty" json:"hiddenWarnings,omitempty"` // Content warnings the user never wants to see
+	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
@@ -16,6 +16,9 @@ func SetupRoutes(
 	userCtrl *controllers.UserController,
 	audiobookCtrl *controllers.AudiobookController,
 	commentCtrl *controllers.CommentController,
+	collectionCtrl *controllers.CollectionController,
+	reviewCtrl *controllers.ReviewController,
+	playlistCtrl *controllers.PlaylistController,
 	adCtrl *controllers.AdController,
 	siteCtrl *controllers.SiteController,
 ) {
@@ -38,17 +41,78 @@ func SetupRoutes(
 	user.Use(middleware.AuthMiddleware(redisClient))
 	user.GET("/profile", userCtrl.GetProfile)
 	user.PUT("/change-password", userCtrl.ChangePassword)
+	user.PUT("/content-preferences", userCtrl.UpdateContentPreferences)
+	user.POST("/followed-authors", userCtrl.FollowAuthor)
+	user.DELETE("/followed-authors/:author", userCtrl.UnfollowAuthor)
+	user.GET("/recommendations", audiobookCtrl.GetRecommendations)
+	user.PUT("/progress", userCtrl.SyncProgress)
+	user.GET("/progress", userCtrl.GetProgress)
+	user.GET("/continue-listening", userCtrl.GetContinueListening)
+	user.GET("/bookmarks", userCtrl.GetBookmarks)
+	user.GET("/bookmarks/export", userCtrl.ExportBookmarks)
+	user.POST("/bookmarks", userCtrl.CreateBookmark)
+	user.PUT("/bookmarks/:id", userCtrl.UpdateBookmark)
+	user.DELETE("/bookmarks/:id", userCtrl.DeleteBookmark)
+	user.GET("/shelves", userCtrl.GetShelves)
+	user.POST("/shelves", userCtrl.CreateShelf)
+	user.GET("/shelves/:shelf", userCtrl.GetShelf)
+	user.PUT("/shelves/:shelf", userCtrl.RenameShelf)
+	user.DELETE("/shelves/:shelf", userCtrl.DeleteShelf)
+	user.POST("/shelves/:shelf/items", userCtrl.AddToShelf)
+	user.DELETE("/shelves/:shelf/items/:audiobookId", userCtrl.RemoveFromShelf)
+	user.POST("/listening-sessions", userCtrl.RecordListeningSession)
+	user.POST("/events", userCtrl.IngestEvents)
+	user.GET("/listening-history", userCtrl.GetListeningHistory)
+	user.GET("/stats", userCtrl.GetListeningStats)
+	user.GET("/wrapped", userCtrl.GetWrapped)
+	user.GET("/playlists", playlistCtrl.GetPlaylists)
+	user.POST("/playlists", playlistCtrl.CreatePlaylist)
+	user.GET("/playlists/:id", playlistCtrl.GetPlaylist)
+	user.PUT("/playlists/:id", playlistCtrl.UpdatePlaylist)
+	user.DELETE("/playlists/:id", playlistCtrl.DeletePlaylist)
+	user.POST("/playlists/:id/share", playlistCtrl.RegenerateShareLink)
+	user.POST("/playlists/:id/collaborators", playlistCtrl.AddCollaborator)
+	user.DELETE("/playlists/:id/collaborators/:userId", playlistCtrl.RemoveCollaborator)
+	user.POST("/playlists/:id/items", playlistCtrl.AddPlaylistItem)
+	user.PUT("/playlists/:id/items", playlistCtrl.ReorderPlaylistItems)
+	user.DELETE("/playlists/:id/items/:itemId", playlistCtrl.RemovePlaylistItem)
+	user.GET("/queue", playlistCtrl.GetQueue)
+	user.POST("/queue", playlistCtrl.Enqueue)
+	user.PUT("/queue", playlistCtrl.ReorderQueue)
+	user.DELETE("/queue", playlistCtrl.ClearQueue)
+	user.POST("/queue/advance", playlistCtrl.AdvanceQueue)
+	user.POST("/queue/playlists/:id", playlistCtrl.EnqueuePlaylist)
+	user.DELETE("/queue/:itemId", playlistCtrl.RemoveFromQueue)
 
 	// ===== Audiobook Routes (replaced Stream routes) =====
 	audiobook := api.Group("/audiobooks")
-	audiobook.GET("", audiobookCtrl.GetAudiobooks)                                                                  // Public - list all audiobooks
-	audiobook.GET("/:id", audiobookCtrl.GetAudiobookByID)                                                           // Public - get audiobook details
-	audiobook.POST("/:id/like", middleware.AuthMiddleware(redisClient), audiobookCtrl.LikeAudiobook)                // Authenticated - like audiobook
-	audiobook.POST("/:id/dislike", middleware.AuthMiddleware(redisClient), audiobookCtrl.DislikeAudiobook)          // Authenticated - dislike audiobook
-	audiobook.GET("/:id/stats", audiobookCtrl.GetAudiobookStats)                                                    // Public - get stats
-	audiobook.POST("/:id/comments", middleware.AuthMiddleware(redisClient), commentCtrl.AddComment)                 // Authenticated - add comment
-	audiobook.GET("/:id/comments", commentCtrl.GetComments)                                                         // Public - get comments
-	audiobook.DELETE("/:id/comments/:commentId", middleware.AuthMiddleware(redisClient), commentCtrl.DeleteComment) // Authenticated - delete comment
+	audiobook.GET("", middleware.OptionalAuthMiddleware(redisClient), audiobookCtrl.GetAudiobooks)                         // Public - list/search audiobooks, filtered by content settings when logged in
+	audiobook.GET("/trending", middleware.OptionalAuthMiddleware(redisClient), audiobookCtrl.GetTrendingAudiobooks)        // Public - trending titles (?window=daily|weekly|monthly)
+	audiobook.GET("/:id", middleware.OptionalAuthMiddleware(redisClient), audiobookCtrl.GetAudiobookByID)                  // Public - get audiobook details
+	audiobook.POST("/:id/plays", middleware.OptionalAuthMiddleware(redisClient), audiobookCtrl.RecordPlay)                 // Public - report a listening session, counted as a view once qualified
+	audiobook.POST("/:id/like", middleware.AuthMiddleware(redisClient), audiobookCtrl.LikeAudiobook)                       // Authenticated - like audiobook
+	audiobook.POST("/:id/dislike", middleware.AuthMiddleware(redisClient), audiobookCtrl.DislikeAudiobook)                 // Authenticated - dislike audiobook
+	audiobook.GET("/:id/related", middleware.OptionalAuthMiddleware(redisClient), audiobookCtrl.GetRelatedAudiobooks)      // Public - related titles
+	audiobook.GET("/:id/stats", audiobookCtrl.GetAudiobookStats)                                                           // Public - get stats
+	audiobook.POST("/:id/comments", middleware.AuthMiddleware(redisClient), commentCtrl.AddComment)                        // Authenticated - add comment
+	audiobook.GET("/:id/comments", commentCtrl.GetComments)                                                                // Public - top-level comments or replies (?parentId=), cursor paginated
+	audiobook.GET("/:id/comments/stream", commentCtrl.StreamComments)                                                      // Public - live comment events (SSE, or WebSocket on upgrade)
+	audiobook.GET("/:id/comments/timeline", commentCtrl.GetTimelineComments)                                               // Public - comments anchored between ?from= and ?to= seconds
+	audiobook.GET("/:id/comments/markers", commentCtrl.GetCommentMarkers)                                                  // Public - comment density along the audio timeline
+	audiobook.PATCH("/:id/comments/:commentId", middleware.AuthMiddleware(redisClient), commentCtrl.EditComment)           // Authenticated - edit own comment (admins: any)
+	audiobook.GET("/:id/comments/:commentId/history", commentCtrl.GetCommentHistory)                                       // Public - earlier versions of an edited comment
+	audiobook.DELETE("/:id/comments/:commentId", middleware.AuthMiddleware(redisClient), commentCtrl.DeleteComment)        // Authenticated - delete own comment (admins: any)
+	audiobook.GET("/:id/reviews", reviewCtrl.GetReviews)                                                                   // Public - reviews and rating summary
+	audiobook.GET("/:id/review", middleware.AuthMiddleware(redisClient), reviewCtrl.GetMyReview)                           // Authenticated - own review
+	audiobook.PUT("/:id/review", middleware.AuthMiddleware(redisClient), reviewCtrl.SaveReview)                            // Authenticated - rate/review (edits the existing one)
+	audiobook.DELETE("/:id/review", middleware.AuthMiddleware(redisClient), reviewCtrl.DeleteMyReview)                     // Authenticated - delete own review
+	audiobook.POST("/:id/reviews/:reviewId/helpful", middleware.AuthMiddleware(redisClient), reviewCtrl.ToggleHelpfulVote) // Authenticated - toggle helpful vote
+
+	// ===== Collection Routes =====
+	collection := api.Group("/collections")
+	collection.GET("/:id", middleware.OptionalAuthMiddleware(redisClient), collectionCtrl.GetCollection)    // Public - collection by ID or slug
+	api.GET("/playlists/shared/:token", playlistCtrl.GetSharedPlaylist)                                     // Public - playlist shared by link
+	api.GET("/home/shelves", middleware.OptionalAuthMiddleware(redisClient), collectionCtrl.GetHomeShelves) // Public - homepage shelf layout
 
 	// ===== Admin Routes =====
 	admin := api.Group("/admin")
@@ -66,7 +130,47 @@ func SetupRoutes(
 	admin.POST("/audiobooks", audiobookCtrl.CreateAudiobook)
 	admin.PUT("/audiobooks/:id", audiobookCtrl.UpdateAudiobook)
 	admin.DELETE("/audiobooks/:id", audiobookCtrl.DeleteAudiobook)
-	admin.GET("/audiobooks", audiobookCtrl.GetAudiobooks) // Admin can also list all
+	admin.GET("/audiobooks", audiobookCtrl.GetAllAudiobooks) // Admin lists audiobooks in any state
+
+	// Admin Audiobook publishing workflow
+	admin.POST("/audiobooks/:id/submit", audiobookCtrl.SubmitAudiobookForReview)
+	admin.POST("/audiobooks/:id/approve", audiobookCtrl.ApproveAudiobook)
+	admin.POST("/audiobooks/:id/reject", audiobookCtrl.RejectAudiobook)
+	admin.POST("/audiobooks/:id/unpublish", audiobookCtrl.UnpublishAudiobook)
+	admin.PUT("/audiobooks/:id/schedule", audiobookCtrl.ScheduleAudiobook)
+
+	// Admin Audiobook translations
+	admin.PUT("/audiobooks/:id/translations/:locale", audiobookCtrl.SetAudiobookTranslation)
+	admin.DELETE("/audiobooks/:id/translations/:locale", audiobookCtrl.DeleteAudiobookTranslation)
+
+	// Admin Audiobook revision history
+	admin.GET("/audiobooks/:id/revisions", audiobookCtrl.GetAudiobookRevisions)
+	admin.GET("/audiobooks/:id/revisions/diff", audiobookCtrl.DiffAudiobookRevisions)
+	admin.POST("/audiobooks/:id/revisions/:revision/rollback", audiobookCtrl.RollbackAudiobook)
+
+	// Admin Audiobook trash
+	admin.GET("/audiobooks/trash", audiobookCtrl.GetTrashedAudiobooks)
+	admin.POST("/audiobooks/:id/restore", audiobookCtrl.RestoreAudiobook)
+	admin.DELETE("/audiobooks/:id/purge", audiobookCtrl.PurgeAudiobook)
+
+	// Admin bulk catalog import
+	admin.POST("/audiobooks/import", audiobookCtrl.ImportAudiobooks)
+	admin.GET("/imports", audiobookCtrl.GetImportJobs)
+	admin.GET("/imports/:id", audiobookCtrl.GetImportJob)
+
+	// Admin catalog export
+	admin.GET("/audiobooks/export", audiobookCtrl.ExportAudiobooks)
+
+	// Admin Collections
+	admin.GET("/collections", collectionCtrl.GetAllCollections)
+	admin.POST("/collections", collectionCtrl.CreateCollection)
+	admin.PUT("/collections/:id", collectionCtrl.UpdateCollection)
+	admin.DELETE("/collections/:id", collectionCtrl.DeleteCollection)
+
+	// Admin Review moderation
+	admin.GET("/reviews", reviewCtrl.GetAllReviews)
+	admin.PUT("/reviews/:id/moderate", reviewCtrl.ModerateReview)
+	admin.DELETE("/reviews/:id", reviewCtrl.DeleteReview)
 
 	// Admin Site_Changes management
 	admin.POST("/site", siteCtrl.CreateSiteChanges)
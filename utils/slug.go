@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const maxSlugLength = 80
+
+var devanagariConsonants = map[rune]string{
+	'क': "k", 'ख': "kh", 'ग': "g", 'घ': "gh", 'ङ': "n",
+	'च': "ch", 'छ': "chh", 'ज': "j", 'झ': "jh", 'ञ': "n",
+	'ट': "t", 'ठ': "th", 'ड': "d", 'ढ': "dh", 'ण': "n",
+	'त': "t", 'थ': "th", 'द': "d", 'ध': "dh", 'न': "n",
+	'प': "p", 'फ': "ph", 'ब': "b", 'भ': "bh", 'म': "m",
+	'य': "y", 'र': "r", 'ल': "l", 'व': "v",
+	'श': "sh", 'ष': "sh", 'स': "s", 'ह': "h",
+	'ऩ': "n", 'ऱ': "r", 'ळ': "l", 'ऴ': "zh",
+}
+
+// devanagariNuktaForms maps a consonant followed by a nukta to its own sound
+var devanagariNuktaForms = map[rune]string{
+	'क': "q", 'ख': "kh", 'ग': "g", 'ज': "z", 'ड': "r", 'ढ': "rh", 'फ': "f",
+}
+
+var devanagariVowels = map[rune]string{
+	'अ': "a", 'आ': "aa", 'इ': "i", 'ई': "ee", 'उ': "u", 'ऊ': "oo",
+	'ऋ': "ri", 'ऎ': "e", 'ए': "e", 'ऐ': "ai", 'ऒ': "o", 'ओ': "o", 'औ': "au",
+}
+
+var devanagariMatras = map[rune]string{
+	'ा': "aa", 'ि': "i", 'ी': "ee", 'ु': "u", 'ू': "oo",
+	'ृ': "ri", 'ॆ': "e", 'े': "e", 'ै': "ai", 'ॊ': "o", 'ो': "o", 'ौ': "au",
+}
+
+// indicFinalConsonants are consonants without an inherent vowel that have no Devanagari counterpart
+var indicFinalConsonants = map[rune]string{
+	'ৎ': "t",                                                   // Bengali khanda ta
+	'ൺ': "n", 'ൻ': "n", 'ർ': "r", 'ൽ': "l", 'ൾ': "l", 'ൿ': "k", // Malayalam chillus
+}
+
+var latinSpecials = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ł': "l", 'þ': "th",
+}
+
+const (
+	devanagariVirama   = '्'
+	devanagariNukta    = '़'
+	devanagariAnusvara = 'ं'
+	devanagariBindu    = 'ँ'
+	devanagariVisarga  = 'ः'
+
+	gurmukhiTippi = 'ੰ' // Nasal sign, like the anusvara
+	gurmukhiAddak = 'ੱ' // Doubles the next consonant; not written
+
+	// Bengali, Gurmukhi, Gujarati, Oriya, Tamil, Telugu, Kannada and Malayalam follow in 0x80 wide blocks
+	indicBlocksStart = 0x0980
+	indicBlocksEnd   = 0x0D7F
+	indicBlockSize   = 0x80
+)
+
+// toDevanagari maps a letter of another Indic script to the Devanagari letter at the same offset
+// of its block, as the scripts share one layout. Other runes are returned unchanged.
+func toDevanagari(r rune) rune {
+	if r == gurmukhiTippi {
+		return devanagariAnusvara
+	}
+	if r < indicBlocksStart || r > indicBlocksEnd {
+		return r
+	}
+	return 0x0900 + (r-0x0900)%indicBlockSize
+}
+
+// Transliterate converts Indic (Devanagari, Bengali, Gurmukhi, Gujarati, Oriya, Tamil, Telugu,
+// Kannada, Malayalam) and accented Latin text to plain ASCII. Characters from other scripts become spaces.
+func Transliterate(s string) string {
+	var out []byte
+	// pendingA tracks the inherent vowel of the last Indic consonant,
+	// which is only written if no matra or virama follows and the word continues
+	pendingA := false
+	var lastConsonant rune
+	lastConsonantAt := 0
+
+	flush := func(next rune) {
+		if pendingA && unicode.IsLetter(next) {
+			out = append(out, 'a')
+		}
+		pendingA = false
+	}
+
+	// NFC keeps nukta forms such as क़ decomposed into consonant + nukta
+	for _, r := range norm.NFC.String(s) {
+		if r == gurmukhiAddak {
+			continue
+		}
+		if c, ok := indicFinalConsonants[r]; ok {
+			flush(r)
+			out = append(out, c...)
+			continue
+		}
+		r = toDevanagari(r)
+		if c, ok := devanagariConsonants[r]; ok {
+			flush(r)
+			lastConsonant, lastConsonantAt = r, len(out)
+			out = append(out, c...)
+			pendingA = true
+			continue
+		}
+		if m, ok := devanagariMatras[r]; ok {
+			pendingA = false
+			out = append(out, m...)
+			continue
+		}
+		switch r {
+		case devanagariNukta:
+			if pendingA {
+				if f, ok := devanagariNuktaForms[lastConsonant]; ok {
+					out = append(out[:lastConsonantAt], f...)
+				}
+			}
+			continue
+		case devanagariVirama:
+			pendingA = false
+			continue
+		case devanagariAnusvara, devanagariBindu:
+			flush('a')
+			out = append(out, 'n')
+			continue
+		case devanagariVisarga:
+			flush('a')
+			out = append(out, 'h')
+			continue
+		}
+
+		flush(r)
+		if v, ok := devanagariVowels[r]; ok {
+			out = append(out, v...)
+			continue
+		}
+		if r >= '०' && r <= '९' {
+			out = append(out, byte('0'+(r-'०')))
+			continue
+		}
+		if sp, ok := latinSpecials[unicode.ToLower(r)]; ok {
+			out = append(out, sp...)
+			continue
+		}
+		for _, d := range norm.NFD.String(string(r)) {
+			if d < unicode.MaxASCII {
+				out = append(out, byte(d))
+			} else if !unicode.Is(unicode.Mn, d) {
+				out = append(out, ' ')
+			}
+		}
+	}
+	return string(out)
+}
+
+// Slugify turns a title into a lowercase, hyphen separated, URL safe slug
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(Transliterate(s)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimSuffix(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
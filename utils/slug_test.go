@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"latin", "The Hobbit: There and Back Again", "the-hobbit-there-and-back-again"},
+		{"accents", "Les Misérables, Tome 1", "les-miserables-tome-1"},
+		{"latin specials", "Straße nach Łódź", "strasse-nach-lodz"},
+		{"devanagari", "गोदान", "godaan"},
+		{"devanagari virama", "हिन्दी कहानी", "hindee-kahaanee"},
+		{"devanagari nukta", "ज़िंदगी", "zindagee"},
+		{"devanagari digits", "भाग २", "bhaag-2"},
+		{"bengali", "নমস্কার", "namaskaar"},
+		{"bengali khanda ta", "উৎসব", "utsab"},
+		{"gurmukhi tippi", "ਪੰਜਾਬ", "panjaab"},
+		{"gurmukhi addak", "ਪੱਤਾ", "pataa"},
+		{"gujarati", "ગુજરાત", "gujaraat"},
+		{"oriya nukta", "ଓଡ଼ିଆ", "oriaa"},
+		{"tamil", "தமிழ்", "tamizh"},
+		{"tamil short vowels", "பொன்னியின் செல்வன்", "ponniyin-chelvan"},
+		{"telugu", "తెలుగు", "telugu"},
+		{"kannada", "ಕನ್ನಡ", "kannad"},
+		{"malayalam chillu", "കേരളൻ", "keralan"},
+		{"malayalam digits", "൧൨", "12"},
+		{"other scripts dropped", "Война и мир 2", "2"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.title); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyLength(t *testing.T) {
+	got := Slugify(strings.Repeat("word ", 40))
+	if len(got) > maxSlugLength {
+		t.Errorf("len = %d, want at most %d", len(got), maxSlugLength)
+	}
+	if strings.HasSuffix(got, "-") {
+		t.Errorf("slug %q ends with a hyphen", got)
+	}
+}
+
+func TestToDevanagari(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want rune
+	}{
+		{'क', 'क'},
+		{'ক', 'क'}, // Bengali
+		{'ਕ', 'क'}, // Gurmukhi
+		{'ક', 'क'}, // Gujarati
+		{'କ', 'क'}, // Oriya
+		{'க', 'क'}, // Tamil
+		{'క', 'क'}, // Telugu
+		{'ಕ', 'क'}, // Kannada
+		{'ക', 'क'}, // Malayalam
+		{'ੰ', 'ं'}, // Gurmukhi tippi
+		{'a', 'a'},
+		{'я', 'я'},
+	}
+	for _, tt := range tests {
+		if got := toDevanagari(tt.r); got != tt.want {
+			t.Errorf("toDevanagari(%q) = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}